@@ -0,0 +1,133 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 构建信息，通过 -ldflags 注入，例如：
+//
+//	go build -ldflags "-X '<module>/buildinfo.Version=1.2.0' \
+//	  -X '<module>/buildinfo.GitCommit=$(git rev-parse --short HEAD)' \
+//	  -X '<module>/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)'"
+//
+// 未注入时，Version 使用 app.version 配置，GitCommit 和 BuildTime 取自 Go 工具链记录的 VCS 信息。
+var (
+	Version   string
+	GitCommit string
+	BuildTime string
+)
+
+// startTime 进程启动时间
+var startTime = time.Now()
+
+var (
+	mu         sync.RWMutex
+	appVersion string
+)
+
+// Info 构建与运行信息
+type Info struct {
+	Version   string    `json:"version"`
+	GitCommit string    `json:"git_commit"`
+	BuildTime string    `json:"build_time"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+	Uptime    string    `json:"uptime"`
+}
+
+// SetAppVersion 设置未通过 ldflags 注入版本号时使用的版本（app.version）
+func SetAppVersion(version string) {
+	mu.Lock()
+	defer mu.Unlock()
+	appVersion = version
+}
+
+// GetVersion 获取应用版本
+func GetVersion() string {
+	if Version != "" {
+		return Version
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return appVersion
+}
+
+// vcsInfo 读取 Go 工具链记录的提交与提交时间，仅在源码目录为 git 仓库时可用
+var vcsInfo = sync.OnceValues(func() (revision, revisionTime string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			revisionTime = setting.Value
+		}
+	}
+	return revision, revisionTime
+})
+
+// Get 获取构建与运行信息
+func Get() Info {
+	info := Info{
+		Version:   GetVersion(),
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		StartTime: startTime,
+		Uptime:    Uptime().Round(time.Second).String(),
+	}
+
+	revision, revisionTime := vcsInfo()
+	if info.GitCommit == "" {
+		info.GitCommit = revision
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = revisionTime
+	}
+	return info
+}
+
+// StartTime 获取进程启动时间
+func StartTime() time.Time {
+	return startTime
+}
+
+// Uptime 获取进程运行时长
+func Uptime() time.Duration {
+	return time.Since(startTime)
+}
+
+// Fields 返回用于日志的构建信息字段
+func Fields() []zap.Field {
+	info := Get()
+	return []zap.Field{
+		zap.String("version", info.Version),
+		zap.String("git_commit", info.GitCommit),
+		zap.String("build_time", info.BuildTime),
+		zap.String("go_version", info.GoVersion),
+	}
+}
+
+// Tags 返回用于链路追踪的构建信息标签
+func Tags() map[string]string {
+	info := Get()
+	tags := map[string]string{
+		"service.version": info.Version,
+		"go.version":      info.GoVersion,
+	}
+	if info.GitCommit != "" {
+		tags["git.commit"] = info.GitCommit
+	}
+	if info.BuildTime != "" {
+		tags["build.time"] = info.BuildTime
+	}
+	return tags
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"go-web-template/config"
+)
+
+// 根据 config.Config 结构生成 app.yaml 的 JSON Schema
+//
+// 用法:
+//
+//	go run cmd/schema/main.go -o config/app.schema.json
+//	go generate ./config
+func main() {
+	output := flag.String("o", "", "输出文件路径（默认输出到标准输出）")
+	flag.Parse()
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal("创建输出文件失败:", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := config.WriteSchema(w); err != nil {
+		log.Fatal("生成JSON Schema失败:", err)
+	}
+}
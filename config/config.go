@@ -2,171 +2,342 @@ package config
 
 import (
 	"fmt"
-	"os"
-
-	"gopkg.in/yaml.v3"
+	"strings"
 )
 
+// 配置结构的字段标签约定：
+//   - desc、enum、min、max 用于生成 JSON Schema（见 schema.go），并在 Validate 中校验取值
+//   - reload:"live" 表示该配置项支持热加载，其余配置项变更后需要重启才能生效
+//   - secret:"true" 表示该配置项为敏感信息，输出生效配置时会被脱敏
+
 // AppConfig 应用配置结构
 type AppConfig struct {
-	Host        string `yaml:"host"`
-	Port        int    `yaml:"port"`
-	Version     string `yaml:"version"`
-	Debug       bool   `yaml:"debug"`
-	Timezone    string `yaml:"timezone"`
-	Environment string `yaml:"environment"`
+	Host        string `yaml:"host" desc:"监听地址"`
+	Port        int    `yaml:"port" desc:"监听端口" min:"1" max:"65535"`
+	Version     string `yaml:"version" desc:"应用版本"`
+	Debug       bool   `yaml:"debug" desc:"调试模式，生产环境必须关闭"`
+	Timezone    string `yaml:"timezone" desc:"时区，如 Asia/Shanghai"`
+	Environment string `yaml:"environment" desc:"运行环境，同时决定加载的 app.<environment>.yaml 覆盖文件"`
 }
 
 // LoggerConfig 日志配置结构
 type LoggerConfig struct {
-	Level      string `yaml:"level"`
-	Format     string `yaml:"format"`
-	Output     string `yaml:"output"`
-	Filename   string `yaml:"filename"`
-	MaxSize    int    `yaml:"max_size"` // MB
-	MaxAge     int    `yaml:"max_age"`  // days
-	MaxBackups int    `yaml:"max_backups"`
-	Compress   bool   `yaml:"compress"`
+	Level      string `yaml:"level" desc:"日志级别" enum:"debug,info,warn,error" reload:"live"`
+	Format     string `yaml:"format" desc:"日志格式" enum:"console,json"`
+	Output     string `yaml:"output" desc:"日志输出，未配置 sinks 时生效" enum:"stdout,file"`
+	Filename   string `yaml:"filename" desc:"日志文件路径，output 为 file 时必填"`
+	MaxSize    int    `yaml:"max_size" desc:"单个日志文件最大尺寸（MB）" min:"0"`
+	MaxAge     int    `yaml:"max_age" desc:"日志文件保留天数" min:"0"`
+	MaxBackups int    `yaml:"max_backups" desc:"保留的旧日志文件数量" min:"0"`
+	Compress   bool   `yaml:"compress" desc:"是否压缩旧日志文件"`
+
+	Sinks []LogSinkConfig `yaml:"sinks" desc:"同时写入的多个日志输出，配置后 output、filename 等单一输出配置不再生效"`
+
+	AccessLog AccessLogConfig `yaml:"access_log" desc:"HTTP访问日志配置"`
+	Redact    RedactConfig    `yaml:"redact" desc:"日志脱敏配置"`
+}
+
+// RedactConfig 日志脱敏配置结构
+type RedactConfig struct {
+	Enabled  bool     `yaml:"enabled" desc:"是否对所有日志输出脱敏" reload:"live"`
+	Fields   []string `yaml:"fields" desc:"整体脱敏的字段名，不区分大小写，字段名包含其中任一项即脱敏" reload:"live"`
+	Patterns []string `yaml:"patterns" desc:"在消息和字段值中脱敏的正则表达式，Bearer 令牌与 JWT 始终脱敏" reload:"live"`
+}
+
+// LogSinkConfig 日志输出配置结构
+type LogSinkConfig struct {
+	Type       string `yaml:"type" desc:"输出类型，syslog 写入本地 syslog/journald 套接字" enum:"stdout,stderr,file,syslog"`
+	Level      string `yaml:"level" desc:"该输出的最低日志级别，为空时只受全局级别限制" enum:",debug,info,warn,error"`
+	Format     string `yaml:"format" desc:"日志格式，为空时使用 logger.format" enum:",console,json"`
+	Filename   string `yaml:"filename" desc:"日志文件路径，type 为 file 时必填"`
+	MaxSize    int    `yaml:"max_size" desc:"单个日志文件最大尺寸（MB），0 表示 100" min:"0"`
+	MaxAge     int    `yaml:"max_age" desc:"日志文件保留天数，0 表示不按时间清理" min:"0"`
+	MaxBackups int    `yaml:"max_backups" desc:"保留的旧日志文件数量，0 表示全部保留" min:"0"`
+	Compress   bool   `yaml:"compress" desc:"是否压缩旧日志文件"`
+	Address    string `yaml:"address" desc:"syslog 套接字地址，为空时自动探测本地 /dev/log 等路径"`
+	Tag        string `yaml:"tag" desc:"syslog 标签，为空时使用进程名"`
+}
+
+// LogSinks 返回生效的日志输出列表，未配置 sinks 时由 output、format 等单一输出配置生成
+func (l *LoggerConfig) LogSinks() []LogSinkConfig {
+	if len(l.Sinks) > 0 {
+		return l.Sinks
+	}
+	return []LogSinkConfig{{
+		Type:       l.Output,
+		Format:     l.Format,
+		Filename:   l.Filename,
+		MaxSize:    l.MaxSize,
+		MaxAge:     l.MaxAge,
+		MaxBackups: l.MaxBackups,
+		Compress:   l.Compress,
+	}}
+}
+
+// AccessLogConfig HTTP访问日志配置结构
+type AccessLogConfig struct {
+	Enabled   bool              `yaml:"enabled" desc:"是否记录访问日志"`
+	SkipPaths []string          `yaml:"skip_paths" desc:"不记录访问日志的请求路径，如 /api/ping" reload:"live"`
+	Sampling  AccessLogSampling `yaml:"sampling" desc:"按响应状态分类的采样率，1 表示全部记录"`
+}
+
+// AccessLogSampling 访问日志按状态分类的采样率
+type AccessLogSampling struct {
+	Success     float64 `yaml:"success" desc:"2xx 响应采样率" min:"0" max:"1" reload:"live"`
+	Redirect    float64 `yaml:"redirect" desc:"3xx 响应采样率" min:"0" max:"1" reload:"live"`
+	ClientError float64 `yaml:"client_error" desc:"4xx 响应采样率" min:"0" max:"1" reload:"live"`
+	ServerError float64 `yaml:"server_error" desc:"5xx 响应采样率" min:"0" max:"1" reload:"live"`
 }
 
 // DatabaseConfig 数据库配置结构
 type DatabaseConfig struct {
-	Host            string `yaml:"host"`
-	Port            int    `yaml:"port"`
-	Username        string `yaml:"username"`
-	Password        string `yaml:"password"`
-	DBName          string `yaml:"dbname"`
-	SSLMode         string `yaml:"sslmode"`
-	MaxIdleConns    int    `yaml:"max_idle_conns"`
-	MaxOpenConns    int    `yaml:"max_open_conns"`
-	ConnMaxLifetime int    `yaml:"conn_max_lifetime"` // minutes
+	Host            string `yaml:"host" desc:"数据库地址"`
+	Port            int    `yaml:"port" desc:"数据库端口" min:"1" max:"65535"`
+	Username        string `yaml:"username" desc:"数据库用户名"`
+	Password        string `yaml:"password" desc:"数据库密码，支持 ${file:...}、${env:...} 密钥引用" secret:"true"`
+	DBName          string `yaml:"dbname" desc:"数据库名称"`
+	SSLMode         string `yaml:"sslmode" desc:"SSL 模式，生产环境禁止 disable" enum:"disable,allow,prefer,require,verify-ca,verify-full"`
+	MaxIdleConns    int    `yaml:"max_idle_conns" desc:"最大空闲连接数" min:"0" reload:"live"`
+	MaxOpenConns    int    `yaml:"max_open_conns" desc:"最大打开连接数，0 表示不限制" min:"0" reload:"live"`
+	ConnMaxLifetime int    `yaml:"conn_max_lifetime" desc:"连接最大存活时间（分钟）" min:"0" reload:"live"`
+	SlowThreshold   int    `yaml:"slow_threshold" desc:"慢查询阈值（毫秒），超过时记录含SQL的警告日志并计入 db_slow_queries_total，0 表示不检测" min:"0"`
+
+	Tracing DatabaseTracingConfig `yaml:"tracing" desc:"数据库操作的链路追踪配置"`
+}
+
+// DatabaseTracingConfig 数据库链路追踪配置结构
+type DatabaseTracingConfig struct {
+	FullSQL      bool `yaml:"full_sql" desc:"db.statement 记录内联绑定变量的完整SQL，关闭时只记录带占位符的SQL，避免敏感数据进入追踪系统"`
+	RowsAffected bool `yaml:"rows_affected" desc:"是否记录受影响的行数 db.rows_affected"`
 }
 
 // JWTConfig JWT配置结构
 type JWTConfig struct {
-	Secret      string `yaml:"secret"`
-	ExpireHours int    `yaml:"expire_hours"`
-	Issuer      string `yaml:"issuer"`
+	Secret      string `yaml:"secret" desc:"JWT 签名密钥，支持 ${file:...}、${env:...} 密钥引用" secret:"true"`
+	ExpireHours int    `yaml:"expire_hours" desc:"token 有效期（小时）" min:"1"`
+	Issuer      string `yaml:"issuer" desc:"token 签发者"`
 }
 
 // ConsulConfig Consul配置结构
 type ConsulConfig struct {
-	Enabled     bool   `yaml:"enabled" json:"enabled"`           // 是否启用Consul
-	Address     string `yaml:"address" json:"address"`           // Consul地址
-	ServiceName string `yaml:"service_name" json:"service_name"` // 服务名称
+	Enabled     bool   `yaml:"enabled" json:"enabled" desc:"是否启用Consul"`
+	Address     string `yaml:"address" json:"address" desc:"Consul地址"`
+	ServiceName string `yaml:"service_name" json:"service_name" desc:"服务名称"`
 }
 
 // ZipkinConfig Zipkin配置结构
 type ZipkinConfig struct {
-	Enabled     bool    `yaml:"enabled"`
-	ServiceName string  `yaml:"service_name"`
-	Endpoint    string  `yaml:"endpoint"`
-	SampleRate  float64 `yaml:"sample_rate"`
+	Enabled     bool                 `yaml:"enabled" desc:"是否启用 Zipkin 链路追踪"`
+	ServiceName string               `yaml:"service_name" desc:"上报的服务名称"`
+	Endpoint    string               `yaml:"endpoint" desc:"Zipkin span 上报地址"`
+	Sampler     string               `yaml:"sampler" desc:"新链路的采样策略：probability 按 sample_rate 计数采样，always 全部采样，never 不采样" enum:"probability,always,never" reload:"live"`
+	SampleRate  float64              `yaml:"sample_rate" desc:"sampler 为 probability 时的采样率，如 0.3 表示每 10 条链路恰好采样 3 条" min:"0" max:"1" reload:"live"`
+	ParentBased bool                 `yaml:"parent_based" desc:"上游请求已决定是否采样时沿用上游的决定" reload:"live"`
+	Rules       []ZipkinSamplingRule `yaml:"rules" desc:"按请求路径的采样规则，按顺序使用第一条匹配的规则" reload:"live"`
+	Reporter    ZipkinReporterConfig `yaml:"reporter" desc:"span 上报配置"`
 }
 
-// Config 总配置结构
-type Config struct {
-	App      AppConfig      `yaml:"app"`
-	Logger   LoggerConfig   `yaml:"logger"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	Consul   ConsulConfig   `yaml:"consul"`
-	Zipkin   ZipkinConfig   `yaml:"zipkin"`
-}
-
-// Load 加载配置文件
-func Load() (*Config, error) {
-	// 读取配置文件
-	data, err := os.ReadFile("config/app.yaml")
-	if err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %w", err)
-	}
+// ZipkinReporterConfig Zipkin span 上报配置结构
+type ZipkinReporterConfig struct {
+	BatchSize     int `yaml:"batch_size" desc:"每批上报的 span 数" min:"1"`
+	BatchInterval int `yaml:"batch_interval" desc:"批次未满时的最长上报间隔（毫秒）" min:"1"`
+	MaxBacklog    int `yaml:"max_backlog" desc:"等待上报的最大 span 数，超出时丢弃最早的 span" min:"1"`
+	Timeout       int `yaml:"timeout" desc:"单次上报请求的超时（毫秒）" min:"1"`
+}
+
+// ZipkinSamplingRule 按请求路径的采样规则
+type ZipkinSamplingRule struct {
+	Path       string  `yaml:"path" desc:"请求路径，以 * 结尾时按前缀匹配，如 /api/private/*"`
+	Sampler    string  `yaml:"sampler" desc:"匹配的请求使用的采样策略，优先于上游的决定；为空时按全局策略" enum:",probability,always,never"`
+	SampleRate float64 `yaml:"sample_rate" desc:"sampler 为 probability 时的采样率" min:"0" max:"1"`
+	Errors     bool    `yaml:"errors" desc:"未被采样的请求以错误（4xx/5xx）结束时仍然上报本服务记录的 span"`
+}
+
+// TracingConfig 链路追踪配置结构
+type TracingConfig struct {
+	Provider string     `yaml:"provider" desc:"链路追踪实现，为空时 zipkin.enabled 为 true 则使用 zipkin，否则不启用" enum:",none,zipkin,otel"`
+	OTel     OTelConfig `yaml:"otel" desc:"OpenTelemetry 配置，provider 为 otel 时生效"`
+}
+
+// OTelConfig OpenTelemetry配置结构
+type OTelConfig struct {
+	ServiceName string  `yaml:"service_name" desc:"上报的服务名称（service.name）"`
+	Exporter    string  `yaml:"exporter" desc:"span 导出方式：otlp 通过 OTLP/HTTP 上报，stdout 输出到标准输出" enum:"otlp,stdout"`
+	Endpoint    string  `yaml:"endpoint" desc:"OTLP/HTTP span 上报地址，如 http://localhost:4318/v1/traces"`
+	SampleRate  float64 `yaml:"sample_rate" desc:"采样率，上游已决定采样时沿用上游的决定" min:"0" max:"1"`
+}
 
-	// 解析 YAML
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+// TracingProvider 返回生效的链路追踪实现（none、zipkin 或 otel），未配置 tracing.provider 时由 zipkin.enabled 决定
+func (c *Config) TracingProvider() string {
+	if c.Tracing.Provider != "" {
+		return c.Tracing.Provider
 	}
+	if c.Zipkin.Enabled {
+		return "zipkin"
+	}
+	return "none"
+}
 
-	// 设置默认值
-	config.setDefaults()
+// CORSConfig 跨域配置结构
+type CORSConfig struct {
+	AllowOrigins []string `yaml:"allow_origins" desc:"允许的跨域来源，为空或包含 * 时允许所有来源" reload:"live"`
+}
+
+// RateLimitConfig 全局限流配置结构
+type RateLimitConfig struct {
+	Enabled           bool    `yaml:"enabled" desc:"是否启用全局限流" reload:"live"`
+	RequestsPerSecond float64 `yaml:"requests_per_second" desc:"每秒允许的请求数" min:"0" reload:"live"`
+	Burst             int     `yaml:"burst" desc:"令牌桶突发容量" min:"0" reload:"live"`
+}
+
+// ReloadConfig 配置热加载结构
+type ReloadConfig struct {
+	Enabled  bool `yaml:"enabled" desc:"是否监听配置文件变化与 SIGHUP 并热加载"`
+	Interval int  `yaml:"interval" desc:"检查配置文件变化的间隔（秒）" min:"0"`
+}
+
+// HealthConfig 健康检查配置结构
+type HealthConfig struct {
+	Timeout      int `yaml:"timeout" desc:"单次健康检查的总超时（毫秒）" min:"1" reload:"live"`
+	CheckTimeout int `yaml:"check_timeout" desc:"单个检查项的默认超时（毫秒），注册检查项时可单独指定" min:"1" reload:"live"`
+	CacheTTL     int `yaml:"cache_ttl" desc:"检查结果缓存时间（毫秒），避免频繁探测压垮数据库，0 表示不缓存" min:"0" reload:"live"`
+}
 
-	return &config, nil
+// MetricsConfig Prometheus 指标配置结构
+type MetricsConfig struct {
+	Enabled bool      `yaml:"enabled" desc:"是否启用 Prometheus 指标"`
+	Path    string    `yaml:"path" desc:"指标暴露路径"`
+	Addr    string    `yaml:"addr" desc:"单独的管理端口监听地址，如 :9090，为空时在应用端口暴露"`
+	Buckets []float64 `yaml:"buckets" desc:"HTTP请求耗时直方图的分桶上界（秒），须递增"`
+}
+
+// UpstreamConfig 出站HTTP调用的上游服务配置结构
+type UpstreamConfig struct {
+	Name         string `yaml:"name" desc:"上游名称，通过 httpclient.Get(name) 获取对应的客户端"`
+	BaseURL      string `yaml:"base_url" desc:"上游服务地址，请求路径相对于该地址解析，如 http://user-service:8080"`
+	Timeout      int    `yaml:"timeout" desc:"单次调用的总超时（毫秒），包含重试，0 表示不限制" min:"0"`
+	Retries      int    `yaml:"retries" desc:"幂等请求遇到网络错误或 502/503/504 时的最大重试次数" min:"0" max:"10"`
+	RetryBackoff int    `yaml:"retry_backoff" desc:"首次重试前的等待时间（毫秒），之后每次翻倍，0 表示 100" min:"0"`
+}
+
+// ShutdownConfig 优雅关闭配置结构，关闭预算按阶段分配，各阶段之和不能超过总超时
+type ShutdownConfig struct {
+	Timeout      int `yaml:"timeout" desc:"优雅关闭的总超时（毫秒），应小于 Kubernetes 的 terminationGracePeriodSeconds" min:"1"`
+	PreStopDelay int `yaml:"pre_stop_delay" desc:"readiness 探针返回 503 后、停止接收请求前的等待时间（毫秒），留给负载均衡摘除流量" min:"0"`
+	HTTP         int `yaml:"http" desc:"等待处理中的HTTP请求完成的时间（毫秒）" min:"1"`
+	CancelGrace  int `yaml:"cancel_grace" desc:"HTTP关闭超时到期前多久取消仍在处理的请求的上下文（毫秒），留给请求结束，须小于 http" min:"0"`
+	Metrics      int `yaml:"metrics" desc:"关闭指标服务器的时间（毫秒），仅在指标使用单独端口时生效" min:"1"`
+	Workers      int `yaml:"workers" desc:"等待后台任务退出的时间（毫秒）" min:"1"`
+	Database     int `yaml:"database" desc:"关闭数据库连接的时间（毫秒）" min:"1"`
+	Tracing      int `yaml:"tracing" desc:"上报剩余 span 的时间（毫秒）" min:"1"`
+}
+
+// Config 总配置结构
+type Config struct {
+	App       AppConfig        `yaml:"app" desc:"应用配置"`
+	Logger    LoggerConfig     `yaml:"logger" desc:"日志配置"`
+	Database  DatabaseConfig   `yaml:"database" desc:"数据库配置"`
+	JWT       JWTConfig        `yaml:"jwt" desc:"JWT配置"`
+	Consul    ConsulConfig     `yaml:"consul" desc:"Consul服务注册配置"`
+	Tracing   TracingConfig    `yaml:"tracing" desc:"链路追踪配置"`
+	Zipkin    ZipkinConfig     `yaml:"zipkin" desc:"Zipkin链路追踪配置"`
+	CORS      CORSConfig       `yaml:"cors" desc:"跨域配置"`
+	RateLimit RateLimitConfig  `yaml:"rate_limit" desc:"全局限流配置"`
+	Reload    ReloadConfig     `yaml:"reload" desc:"配置热加载"`
+	Health    HealthConfig     `yaml:"health" desc:"健康检查配置"`
+	Metrics   MetricsConfig    `yaml:"metrics" desc:"Prometheus 指标配置"`
+	Upstreams []UpstreamConfig `yaml:"upstreams" desc:"出站HTTP调用的上游服务"`
+	Shutdown  ShutdownConfig   `yaml:"shutdown" desc:"优雅关闭配置"`
 }
 
 // setDefaults 设置默认值
+//
+// 在读取配置文件之前调用，配置文件和环境变量中出现的字段会覆盖这里的默认值。
 func (c *Config) setDefaults() {
 	// App 默认值
-	if c.App.Timezone == "" {
-		c.App.Timezone = "UTC"
-	}
-	if c.App.Environment == "" {
-		c.App.Environment = "development"
-	}
+	c.App.Timezone = "UTC"
+	c.App.Environment = "development"
 
 	// Logger 默认值
-	if c.Logger.Level == "" {
-		c.Logger.Level = "info"
-	}
-	if c.Logger.Format == "" {
-		c.Logger.Format = "console"
-	}
-	if c.Logger.Output == "" {
-		c.Logger.Output = "stdout"
-	}
-	if c.Logger.MaxSize == 0 {
-		c.Logger.MaxSize = 100
-	}
-	if c.Logger.MaxAge == 0 {
-		c.Logger.MaxAge = 30
+	c.Logger.Level = "info"
+	c.Logger.Format = "console"
+	c.Logger.Output = "stdout"
+	c.Logger.MaxSize = 100
+	c.Logger.MaxAge = 30
+	c.Logger.MaxBackups = 3
+	c.Logger.AccessLog.Enabled = true
+	c.Logger.AccessLog.Sampling = AccessLogSampling{
+		Success:     1,
+		Redirect:    1,
+		ClientError: 1,
+		ServerError: 1,
 	}
-	if c.Logger.MaxBackups == 0 {
-		c.Logger.MaxBackups = 3
+	c.Logger.Redact.Enabled = true
+	c.Logger.Redact.Fields = []string{"authorization", "password", "token", "secret", "email"}
+	c.Logger.Redact.Patterns = []string{
+		`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, // 邮箱
+		`\$2[aby]?\$\d{2}\$[./A-Za-z0-9]{53}`,            // bcrypt 密码哈希
 	}
 
 	// Database 默认值
-	if c.Database.SSLMode == "" {
-		c.Database.SSLMode = "disable"
-	}
-	if c.Database.MaxIdleConns == 0 {
-		c.Database.MaxIdleConns = 10
-	}
-	if c.Database.MaxOpenConns == 0 {
-		c.Database.MaxOpenConns = 100
-	}
-	if c.Database.ConnMaxLifetime == 0 {
-		c.Database.ConnMaxLifetime = 60
-	}
+	c.Database.SSLMode = "disable"
+	c.Database.MaxIdleConns = 10
+	c.Database.MaxOpenConns = 100
+	c.Database.ConnMaxLifetime = 60
+	c.Database.SlowThreshold = 1000
+	c.Database.Tracing.RowsAffected = true
 
 	// JWT 默认值
-	if c.JWT.Secret == "" {
-		c.JWT.Secret = "change-this-secret-key-in-production"
-	}
-	if c.JWT.ExpireHours == 0 {
-		c.JWT.ExpireHours = 24
-	}
-	if c.JWT.Issuer == "" {
-		c.JWT.Issuer = "go-web-template"
-	}
+	c.JWT.Secret = DefaultJWTSecret
+	c.JWT.ExpireHours = 24
+	c.JWT.Issuer = "go-web-template"
 
 	// Consul 默认值
-	if c.Consul.ServiceName == "" {
-		c.Consul.ServiceName = "go-web-template"
-	}
-	if c.Consul.Address == "" {
-		c.Consul.Address = "http://localhost:8500"
-	}
+	c.Consul.ServiceName = "go-web-template"
+	c.Consul.Address = "http://localhost:8500"
 
 	// Zipkin 默认值
-	if c.Zipkin.ServiceName == "" {
-		c.Zipkin.ServiceName = "go-web-template"
-	}
-	if c.Zipkin.Endpoint == "" {
-		c.Zipkin.Endpoint = "http://localhost:9411/api/v2/spans"
-	}
-	if c.Zipkin.SampleRate == 0 {
-		c.Zipkin.SampleRate = 1.0
-	}
+	c.Zipkin.ServiceName = "go-web-template"
+	c.Zipkin.Endpoint = "http://localhost:9411/api/v2/spans"
+	c.Zipkin.Sampler = "probability"
+	c.Zipkin.SampleRate = 1.0
+	c.Zipkin.ParentBased = true
+	c.Zipkin.Reporter.BatchSize = 100
+	c.Zipkin.Reporter.BatchInterval = 1000
+	c.Zipkin.Reporter.MaxBacklog = 1000
+	c.Zipkin.Reporter.Timeout = 5000
+
+	// Tracing 默认值
+	c.Tracing.OTel.ServiceName = "go-web-template"
+	c.Tracing.OTel.Exporter = "otlp"
+	c.Tracing.OTel.Endpoint = "http://localhost:4318/v1/traces"
+	c.Tracing.OTel.SampleRate = 1.0
+
+	// RateLimit 默认值
+	c.RateLimit.RequestsPerSecond = 100
+	c.RateLimit.Burst = 200
+
+	// Reload 默认值
+	c.Reload.Interval = 5
+
+	// Health 默认值
+	c.Health.Timeout = 3000
+	c.Health.CheckTimeout = 1000
+	c.Health.CacheTTL = 2000
+
+	// Metrics 默认值
+	c.Metrics.Enabled = true
+	c.Metrics.Path = "/metrics"
+	c.Metrics.Buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	// Shutdown 默认值
+	c.Shutdown.Timeout = 16000
+	c.Shutdown.HTTP = 8000
+	c.Shutdown.CancelGrace = 1000
+	c.Shutdown.Metrics = 1000
+	c.Shutdown.Workers = 2000
+	c.Shutdown.Database = 2000
+	c.Shutdown.Tracing = 3000
 }
 
 // GetAddr 获取完整的监听地址
@@ -176,12 +347,26 @@ func (c *Config) GetAddr() string {
 
 // GetDSN 获取数据库连接字符串
 func (c *Config) GetDSN() string {
+	return c.Database.DSN()
+}
+
+// DSN 构建 PostgreSQL 连接字符串
+//
+// 各项值按 libpq 规则加单引号转义，密码等从密钥文件读取的值包含空格或引号时也能正确解析。
+func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		c.Database.Host,
-		c.Database.Port,
-		c.Database.Username,
-		c.Database.Password,
-		c.Database.DBName,
-		c.Database.SSLMode,
+		quoteDSNValue(d.Host),
+		d.Port,
+		quoteDSNValue(d.Username),
+		quoteDSNValue(d.Password),
+		quoteDSNValue(d.DBName),
+		quoteDSNValue(d.SSLMode),
 	)
 }
+
+// quoteDSNValue 按 libpq key=value 格式转义值
+func quoteDSNValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
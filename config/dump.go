@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置项来源类型
+const (
+	SourceDefault = "default" // setDefaults 默认值
+	SourceFile    = "file"    // 配置文件或环境覆盖文件
+	SourceEnv     = "env"     // APP_ 前缀环境变量
+)
+
+// maskedValue 脱敏后的占位值
+const maskedValue = "******"
+
+// Source 配置项的来源
+type Source struct {
+	Kind   string // default、file、env
+	Origin string // 文件路径或环境变量名
+	Secret bool   // 值由 ${scheme:ref} 密钥引用解析得到
+}
+
+// String 返回来源描述，如 file:config/app.yaml、env:APP_JWT_SECRET
+func (s Source) String() string {
+	desc := s.Kind
+	if s.Origin != "" {
+		desc += ":" + s.Origin
+	}
+	if s.Secret {
+		desc += " (secret ref)"
+	}
+	return desc
+}
+
+// Sources 各配置项（以 YAML 路径为键）的来源
+type Sources map[string]Source
+
+// EffectiveValue 生效配置项的取值与来源
+type EffectiveValue struct {
+	Value  interface{} `json:"value" yaml:"value"`
+	Source string      `json:"source" yaml:"source"`
+}
+
+// Effective 返回当前生效配置的脱敏视图，结构与 app.yaml 一致，叶子节点为取值与来源
+//
+// 标记 secret:"true" 的字段和由密钥引用解析得到的值会被脱敏。
+func Effective() map[string]interface{} {
+	s := current.Load()
+	if s == nil {
+		return nil
+	}
+
+	root := make(map[string]interface{})
+	walkFields(reflect.ValueOf(s.config).Elem(), "", func(path string, field reflect.StructField, fv reflect.Value) error {
+		source := s.sources[path]
+		value := fv.Interface()
+		if (field.Tag.Get("secret") == "true" || source.Secret) && !fv.IsZero() {
+			value = maskedValue
+		}
+		setPath(root, path, EffectiveValue{Value: value, Source: source.String()})
+		return nil
+	})
+	return root
+}
+
+// WriteEffective 以 yaml 或 json 格式输出当前生效配置的脱敏视图
+func WriteEffective(w io.Writer, format string) error {
+	effective := Effective()
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(effective)
+	case "yaml", "":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(effective)
+	default:
+		return fmt.Errorf("不支持的输出格式 %q，可选值: yaml, json", format)
+	}
+}
+
+// setPath 按 a.b.c 路径在嵌套 map 中写入值
+func setPath(root map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	m := root
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[key] = child
+		}
+		m = child
+	}
+	m[keys[len(keys)-1]] = value
+}
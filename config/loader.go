@@ -0,0 +1,297 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置加载顺序（后者覆盖前者）：
+//  1. setDefaults 设置的默认值
+//  2. YAML 配置文件（-config 参数 > APP_CONFIG 环境变量 > config/app.yaml）
+//  3. 与 app.environment 对应的环境覆盖文件，例如 config/app.production.yaml
+//  4. APP_ 前缀的环境变量，例如 APP_DATABASE_PASSWORD 覆盖 database.password
+//
+// 合并完成后，字符串配置中的 ${file:/run/secrets/db_password}、${env:JWT_SECRET}
+// 等密钥引用会通过已注册的 SecretProvider 解析为实际值。
+
+const (
+	// DefaultConfigPath 默认配置文件路径
+	DefaultConfigPath = "config/app.yaml"
+	// EnvConfigPath 指定配置文件路径的环境变量
+	EnvConfigPath = "APP_CONFIG"
+	// EnvPrefix 配置项环境变量前缀
+	EnvPrefix = "APP"
+)
+
+// configPath 通过 -config 命令行参数指定的配置文件路径
+var configPath string
+
+// configSchema 由 Config 结构生成的 JSON Schema，用于校验配置文件结构
+var configSchema = sync.OnceValue(GenerateSchema)
+
+func init() {
+	flag.StringVar(&configPath, "config", "", "配置文件路径（未指定时读取 APP_CONFIG 环境变量，默认 config/app.yaml）")
+}
+
+// Path 返回配置文件路径，以及该路径是否由 -config 参数或 APP_CONFIG 显式指定
+func Path() (string, bool) {
+	if configPath != "" {
+		return configPath, true
+	}
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path, true
+	}
+	return DefaultConfigPath, false
+}
+
+// Load 按 默认值 -> 配置文件 -> 环境覆盖文件 -> 环境变量 的顺序加载配置
+//
+// 调用前需要先执行 flag.Parse()，-config 参数才会生效。
+// 显式指定的配置文件必须存在；默认路径的文件缺失时仅使用默认值和环境变量，
+// 便于容器中完全通过环境变量注入配置。
+// 加载成功的配置会作为 Current() 返回的初始快照。
+func Load() (*Config, error) {
+	path, explicit := Path()
+	config, sources, err := load(path, explicit)
+	if err != nil {
+		return nil, err
+	}
+	current.Store(&snapshot{config: config, sources: sources})
+	return config, nil
+}
+
+// load 执行分层加载，同时记录每个配置项的来源
+func load(path string, required bool) (*Config, Sources, error) {
+	var config Config
+	sources := make(Sources)
+
+	// 1. 默认值
+	config.setDefaults()
+	walkFields(reflect.ValueOf(&config).Elem(), "", func(path string, _ reflect.StructField, _ reflect.Value) error {
+		sources[path] = Source{Kind: SourceDefault}
+		return nil
+	})
+
+	// 2. 基础配置文件
+	if err := mergeFile(&config, sources, path, required); err != nil {
+		return nil, nil, err
+	}
+
+	// 3. 环境配置覆盖文件，如 app.production.yaml
+	// 环境名允许由 APP_APP_ENVIRONMENT 环境变量指定，因此需要在合并覆盖文件之前确定
+	environment := config.App.Environment
+	if env, ok := os.LookupEnv(EnvKey("app.environment")); ok {
+		environment = env
+	}
+	if environment != "" {
+		if err := mergeFile(&config, sources, ProfilePath(path, environment), false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 4. 环境变量
+	if err := applyEnv(&config, sources); err != nil {
+		return nil, nil, err
+	}
+
+	// 5. 解析 ${file:...}、${env:...} 等密钥引用
+	if err := resolveSecrets(&config, sources); err != nil {
+		return nil, nil, err
+	}
+
+	return &config, sources, nil
+}
+
+// mergeFile 将 YAML 文件合并到已有配置上
+//
+// yaml.v3 解码到已有结构体时只会覆盖文件中出现的字段，嵌套结构体逐字段深度合并，
+// 因此文件中显式写出的零值（如 debug: false）同样会生效。
+// required 为 false 时文件不存在不视为错误。
+func mergeFile(config *Config, sources Sources, path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("解析配置文件失败 %s: %w", path, err)
+	}
+
+	// 按 JSON Schema 检查文件结构，拒绝未知配置项（yaml.Unmarshal 默认会静默忽略）
+	v := &validator{}
+	checkNode(configSchema(), &node, "", v)
+	if len(v.errors) > 0 {
+		return fmt.Errorf("配置文件 %s 不合法: %w", path, &ValidationError{Errors: v.errors})
+	}
+
+	if err := node.Decode(config); err != nil {
+		return fmt.Errorf("解析配置文件失败 %s: %w", path, err)
+	}
+
+	// 记录文件中出现的配置项
+	for _, key := range nodePaths(&node, "") {
+		if _, ok := sources[key]; ok {
+			sources[key] = Source{Kind: SourceFile, Origin: path}
+		}
+	}
+	return nil
+}
+
+// nodePaths 返回 YAML 文档中所有叶子节点的路径
+func nodePaths(node *yaml.Node, prefix string) []string {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return nodePaths(node.Content[0], prefix)
+	case yaml.MappingNode:
+		var paths []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			paths = append(paths, nodePaths(node.Content[i+1], key)...)
+		}
+		return paths
+	default:
+		return []string{prefix}
+	}
+}
+
+// ProfilePath 返回指定环境的覆盖文件路径，例如 config/app.yaml + production -> config/app.production.yaml
+func ProfilePath(path, environment string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + environment + ext
+}
+
+// applyEnv 使用 APP_ 前缀 + 大写 YAML 路径形式的环境变量覆盖字段值
+func applyEnv(config *Config, sources Sources) error {
+	return walkFields(reflect.ValueOf(config).Elem(), "", func(path string, _ reflect.StructField, fv reflect.Value) error {
+		key := EnvKey(path)
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			return nil
+		}
+		// 结构体列表（如 logger.sinks、upstreams、zipkin.rules）无法用单个字符串表示
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Struct {
+			return fmt.Errorf("环境变量 %s 无法设置 %s: 结构体列表只能在配置文件中配置", key, path)
+		}
+		if err := setFromString(fv, raw); err != nil {
+			return fmt.Errorf("环境变量 %s 解析失败: %w", key, err)
+		}
+		sources[path] = Source{Kind: SourceEnv, Origin: key}
+		return nil
+	})
+}
+
+// EnvKey 返回配置项对应的环境变量名，例如 database.password -> APP_DATABASE_PASSWORD
+func EnvKey(path string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// walkFields 递归遍历配置结构中的叶子字段，path 为以 . 连接的 YAML 路径
+func walkFields(v reflect.Value, prefix string, fn func(path string, field reflect.StructField, fv reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			if err := walkFields(fv, path, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(path, field, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFromString 将字符串值转换为字段类型并赋值
+func setFromString(fv reflect.Value, raw string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		// 切片使用逗号分隔，例如 APP_CORS_ALLOW_ORIGINS=a.com,b.com
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(fv.Type(), 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if err := setFromString(elem, part); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fv.Set(slice)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", fv.Type())
+	}
+	return nil
+}
+
+// yamlName 返回字段在 YAML 中的键名
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ChangeFunc 配置变更回调，prev 为变更前的快照，next 为新生效的快照
+type ChangeFunc func(prev, next *Config)
+
+// FieldChange 单个配置项的变更
+type FieldChange struct {
+	Path string // YAML 路径
+	Live bool   // 是否支持热加载
+}
+
+// snapshot 配置快照及各配置项来源
+type snapshot struct {
+	config  *Config
+	sources Sources
+}
+
+var (
+	// current 当前生效的配置快照，热加载时整体原子替换
+	current atomic.Pointer[snapshot]
+
+	subscribersMu sync.RWMutex
+	subscribers   []ChangeFunc
+
+	// reloadMu 保证同一时间只有一次热加载
+	reloadMu sync.Mutex
+)
+
+// Current 返回当前生效的配置快照，调用方不应修改返回值
+func Current() *Config {
+	if s := current.Load(); s != nil {
+		return s.config
+	}
+	return nil
+}
+
+// OnChange 注册配置变更回调，回调在热加载成功后按注册顺序同步执行
+func OnChange(fn ChangeFunc) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Reload 重新加载并校验配置，校验通过后原子替换当前快照并通知订阅者
+//
+// 不支持热加载的配置项发生变化时只记录警告，新快照中保留原值，
+// 保证 Current() 始终反映实际生效的配置。
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	path, explicit := Path()
+	next, sources, err := load(path, explicit)
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	old := current.Load()
+	if old == nil {
+		current.Store(&snapshot{config: next, sources: sources})
+		return nil
+	}
+	prev := old.config
+
+	changes := diffFields(reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem(), "")
+	if len(changes) == 0 {
+		zap.L().Debug("配置文件未发生变化")
+		return nil
+	}
+
+	live := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.Live {
+			live = append(live, change.Path)
+			continue
+		}
+		sources[change.Path] = old.sources[change.Path]
+		zap.L().Warn("配置项变更需要重启才能生效", zap.String("field", change.Path))
+	}
+	if len(live) == 0 {
+		return nil
+	}
+
+	current.Store(&snapshot{config: next, sources: sources})
+	zap.L().Info("配置热加载成功", zap.Strings("fields", live))
+
+	subscribersMu.RLock()
+	fns := append([]ChangeFunc(nil), subscribers...)
+	subscribersMu.RUnlock()
+	for _, fn := range fns {
+		notify(fn, prev, next)
+	}
+	return nil
+}
+
+// notify 执行单个回调，避免回调 panic 影响其他订阅者
+func notify(fn ChangeFunc, prev, next *Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			zap.L().Error("配置变更回调执行时发生panic", zap.Any("panic", r))
+		}
+	}()
+	fn(prev, next)
+}
+
+// diffFields 比较两份配置的叶子字段
+//
+// 不支持热加载的字段会被还原为 prev 中的值。
+func diffFields(prev, next reflect.Value, prefix string) []FieldChange {
+	var changes []FieldChange
+	t := prev.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := yamlName(field)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		pv, nv := prev.Field(i), next.Field(i)
+		if pv.Kind() == reflect.Struct {
+			changes = append(changes, diffFields(pv, nv, path)...)
+			continue
+		}
+		if reflect.DeepEqual(pv.Interface(), nv.Interface()) {
+			continue
+		}
+
+		live := field.Tag.Get("reload") == "live"
+		if !live {
+			nv.Set(pv)
+		}
+		changes = append(changes, FieldChange{Path: path, Live: live})
+	}
+	return changes
+}
+
+// Watch 监听配置文件变化和 SIGHUP 信号并触发热加载，阻塞直到 ctx 取消
+//
+// 通过定期比较文件的修改时间和大小判断是否变化，兼容 Kubernetes ConfigMap 的符号链接替换。
+func Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := fingerprint()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			zap.L().Info("接收到SIGHUP信号，重新加载配置")
+		case <-ticker.C:
+			fp := fingerprint()
+			if fp == last {
+				continue
+			}
+			last = fp
+			zap.L().Info("检测到配置文件变化，重新加载配置")
+		}
+
+		if err := Reload(); err != nil {
+			zap.L().Error("配置热加载失败，继续使用当前配置", zap.Error(err))
+		}
+	}
+}
+
+// fingerprint 返回基础配置文件和环境覆盖文件的修改时间与大小
+func fingerprint() string {
+	path, _ := Path()
+	files := []string{path}
+	if cfg := Current(); cfg != nil && cfg.App.Environment != "" {
+		files = append(files, ProfilePath(path, cfg.App.Environment))
+	}
+
+	var fp string
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fp += fmt.Sprintf("%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return fp
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../cmd/schema -o app.schema.json
+
+// schemaDraft 生成的 JSON Schema 版本
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Schema JSON Schema 节点，仅包含本项目用到的关键字
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// GenerateSchema 根据 Config 结构及其 yaml、desc、enum、min、max 标签生成 JSON Schema，
+// 默认值取自 setDefaults
+func GenerateSchema() *Schema {
+	var defaults Config
+	defaults.setDefaults()
+
+	schema := objectSchema(reflect.ValueOf(defaults))
+	schema.Schema = schemaDraft
+	schema.Title = "app.yaml"
+	schema.Description = "应用配置文件"
+	return schema
+}
+
+// WriteSchema 以缩进 JSON 格式输出 JSON Schema
+func WriteSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(GenerateSchema())
+}
+
+// objectSchema 生成结构体对应的 object 节点，不允许出现未声明的属性
+func objectSchema(v reflect.Value) *Schema {
+	closed := false
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: &closed,
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		var prop *Schema
+		if fv.Kind() == reflect.Struct {
+			prop = objectSchema(fv)
+		} else {
+			prop = valueSchema(fv.Type(), field)
+			if !fv.IsZero() {
+				prop.Default = fv.Interface()
+			}
+		}
+		prop.Description = field.Tag.Get("desc")
+		schema.Properties[name] = prop
+	}
+	return schema
+}
+
+// valueSchema 生成叶子字段对应的节点
+func valueSchema(t reflect.Type, field reflect.StructField) *Schema {
+	schema := &Schema{Type: jsonType(t)}
+	if t.Kind() == reflect.Slice {
+		if t.Elem().Kind() == reflect.Struct {
+			schema.Items = objectSchema(reflect.New(t.Elem()).Elem())
+		} else {
+			schema.Items = &Schema{Type: jsonType(t.Elem())}
+		}
+		return schema
+	}
+
+	if enum := field.Tag.Get("enum"); enum != "" {
+		for _, e := range strings.Split(enum, ",") {
+			schema.Enum = append(schema.Enum, e)
+		}
+	}
+	schema.Minimum = tagFloat(field, "min")
+	schema.Maximum = tagFloat(field, "max")
+	return schema
+}
+
+// jsonType 返回 Go 类型对应的 JSON Schema 类型
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// tagFloat 解析数值类型的字段标签
+func tagFloat(field reflect.StructField, key string) *float64 {
+	raw, ok := field.Tag.Lookup(key)
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		panic(fmt.Sprintf("字段 %s 的 %s 标签不是合法数值: %q", field.Name, key, raw))
+	}
+	return &f
+}
+
+// checkNode 按 Schema 检查 YAML 文档结构，未声明的配置项视为错误
+//
+// 取值的枚举与范围约束在 Validate 中针对合并后的配置统一校验。
+func checkNode(schema *Schema, node *yaml.Node, path string, v *validator) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			checkNode(schema, child, path, v)
+		}
+	case yaml.MappingNode:
+		if schema.Type != "object" {
+			v.add(path, "第 %d 行: 应为 %s 类型，实际为对象", node.Line, schema.Type)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			prop, ok := schema.Properties[key.Value]
+			if !ok {
+				v.add(keyPath, "第 %d 行: 未知的配置项", key.Line)
+				continue
+			}
+			checkNode(prop, node.Content[i+1], keyPath, v)
+		}
+	case yaml.SequenceNode:
+		if schema.Type != "array" {
+			v.add(path, "第 %d 行: 应为 %s 类型，实际为列表", node.Line, schema.Type)
+			return
+		}
+		if schema.Items == nil {
+			return
+		}
+		for i, child := range node.Content {
+			checkNode(schema.Items, child, fmt.Sprintf("%s[%d]", path, i), v)
+		}
+	case yaml.ScalarNode:
+		// 空值（如 cors: 下无内容）允许出现在对象位置
+		if schema.Type == "object" && node.Tag != "!!null" {
+			v.add(path, "第 %d 行: 应为对象", node.Line)
+		}
+	}
+}
+
+// checkConstraints 按字段标签中的 enum、min、max 校验配置取值
+func checkConstraints(config *Config, v *validator) {
+	var check func(path string, field reflect.StructField, fv reflect.Value) error
+	check = func(path string, field reflect.StructField, fv reflect.Value) error {
+		// 结构体列表逐项校验，如 logger.sinks[0].type
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Struct {
+			for i := 0; i < fv.Len(); i++ {
+				walkFields(fv.Index(i), fmt.Sprintf("%s[%d]", path, i), check)
+			}
+			return nil
+		}
+
+		if enum := field.Tag.Get("enum"); enum != "" {
+			value := fmt.Sprint(fv.Interface())
+			allowed := strings.Split(enum, ",")
+			found := false
+			for _, a := range allowed {
+				if value == a {
+					found = true
+					break
+				}
+			}
+			if !found {
+				v.add(path, "取值 %q 不合法，可选值: %s", value, strings.Join(allowed, ", "))
+			}
+		}
+
+		var n float64
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(fv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = float64(fv.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = fv.Float()
+		default:
+			return nil
+		}
+		if min := tagFloat(field, "min"); min != nil && n < *min {
+			v.add(path, "不能小于 %g: %g", *min, n)
+		}
+		if max := tagFloat(field, "max"); max != nil && n > *max {
+			v.add(path, "不能大于 %g: %g", *max, n)
+		}
+		return nil
+	}
+	walkFields(reflect.ValueOf(config).Elem(), "", check)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretResolveTimeout 单次配置加载中解析全部密钥引用的超时时间
+const secretResolveTimeout = 10 * time.Second
+
+// secretRefPattern 匹配 ${scheme:ref} 形式的密钥引用
+var secretRefPattern = regexp.MustCompile(`\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]*)\}`)
+
+// SecretProvider 密钥提供者
+//
+// 配置值中的 ${scheme:ref} 会交给注册在 scheme 下的提供者解析，
+// 例如接入 Vault 时可注册 vault 提供者解析 ${vault:secret/data/app#db_password}。
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc 函数形式的 SecretProvider，便于在测试中伪造
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve 实现 SecretProvider
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file": SecretProviderFunc(fileSecret),
+		"env":  SecretProviderFunc(envSecret),
+	}
+)
+
+// RegisterSecretProvider 注册密钥提供者，同名 scheme 会被覆盖
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[scheme] = provider
+}
+
+// getSecretProvider 获取指定 scheme 的密钥提供者
+func getSecretProvider(scheme string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	provider, ok := secretProviders[scheme]
+	return provider, ok
+}
+
+// fileSecret 从文件读取密钥（如 Docker/Kubernetes secrets），去除末尾换行
+func fileSecret(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// envSecret 从环境变量读取密钥，变量未设置视为错误
+func envSecret(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("环境变量 %s 未设置", ref)
+	}
+	return value, nil
+}
+
+// ResolveSecret 解析字符串中的所有密钥引用
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	var resolveErr error
+	resolved := secretRefPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		parts := secretRefPattern.FindStringSubmatch(match)
+		scheme, ref := parts[1], parts[2]
+
+		provider, ok := getSecretProvider(scheme)
+		if !ok {
+			resolveErr = fmt.Errorf("未注册的密钥提供者 %q", scheme)
+			return match
+		}
+		secret, err := provider.Resolve(ctx, ref)
+		if err != nil {
+			resolveErr = fmt.Errorf("解析密钥引用 ${%s:%s} 失败: %w", scheme, ref, err)
+			return match
+		}
+		return secret
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// resolveSecrets 解析配置中所有字符串字段的密钥引用
+func resolveSecrets(config *Config, sources Sources) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	return walkFields(reflect.ValueOf(config).Elem(), "", func(path string, _ reflect.StructField, fv reflect.Value) error {
+		if fv.Kind() != reflect.String || !strings.Contains(fv.String(), "${") {
+			return nil
+		}
+		resolved, err := ResolveSecret(ctx, fv.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fv.SetString(resolved)
+
+		source := sources[path]
+		source.Secret = true
+		sources[path] = source
+		return nil
+	})
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultJWTSecret setDefaults 使用的占位 JWT 密钥，生产环境禁止使用
+const DefaultJWTSecret = "change-this-secret-key-in-production"
+
+// placeholderJWTSecrets 模板中出现过的占位密钥
+var placeholderJWTSecrets = []string{
+	DefaultJWTSecret,
+	"your-secret-key-change-in-production",
+}
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Path    string // YAML 路径，如 database.max_open_conns
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationError 聚合的配置校验错误，包含所有不合法的配置项
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "配置校验失败（%d 项）:", len(e.Errors))
+	for _, fe := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(fe.Error())
+	}
+	return b.String()
+}
+
+// validator 收集校验错误
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) url(path, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		v.add(path, "不是合法的 URL: %q", raw)
+	}
+}
+
+// IsProduction 是否为生产环境
+func (c *Config) IsProduction() bool {
+	return c.App.Environment == "production"
+}
+
+// HasPlaceholderJWTSecret JWT 密钥是否仍为模板占位值
+func (c *Config) HasPlaceholderJWTSecret() bool {
+	for _, placeholder := range placeholderJWTSecrets {
+		if c.JWT.Secret == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 校验配置，一次性返回所有错误（*ValidationError），全部合法时返回 nil
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// 字段标签声明的枚举与取值范围
+	checkConstraints(c, v)
+
+	// App
+	if _, err := time.LoadLocation(c.App.Timezone); err != nil {
+		v.add("app.timezone", "无法加载时区 %q: %v", c.App.Timezone, err)
+	}
+	if c.App.Environment == "" {
+		v.add("app.environment", "不能为空")
+	}
+
+	// Logger
+	if len(c.Logger.Sinks) == 0 && c.Logger.Output == "file" && c.Logger.Filename == "" {
+		v.add("logger.filename", "output 为 file 时不能为空")
+	}
+	for i, pattern := range c.Logger.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.add(fmt.Sprintf("logger.redact.patterns[%d]", i), "正则表达式不合法: %v", err)
+		}
+	}
+	for i, sink := range c.Logger.Sinks {
+		if sink.Type == "file" && sink.Filename == "" {
+			v.add(fmt.Sprintf("logger.sinks[%d].filename", i), "type 为 file 时不能为空")
+		}
+	}
+
+	// Metrics
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		v.add("metrics.path", "必须以 / 开头")
+	}
+	for i := 1; i < len(c.Metrics.Buckets); i++ {
+		if c.Metrics.Buckets[i] <= c.Metrics.Buckets[i-1] {
+			v.add("metrics.buckets", "分桶上界必须递增")
+			break
+		}
+	}
+
+	// Database
+	if c.Database.Host == "" {
+		v.add("database.host", "不能为空")
+	}
+	if c.Database.DBName == "" {
+		v.add("database.dbname", "不能为空")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		v.add("database.max_idle_conns", "不能大于 max_open_conns (%d > %d)",
+			c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	// JWT
+	if c.JWT.Secret == "" {
+		v.add("jwt.secret", "不能为空")
+	}
+
+	// Consul
+	if c.Consul.Enabled {
+		v.url("consul.address", c.Consul.Address)
+	}
+
+	// Tracing
+	switch c.TracingProvider() {
+	case "zipkin":
+		v.url("zipkin.endpoint", c.Zipkin.Endpoint)
+		if c.Zipkin.ServiceName == "" {
+			v.add("zipkin.service_name", "启用 Zipkin 时不能为空")
+		}
+		for i, rule := range c.Zipkin.Rules {
+			if !strings.HasPrefix(rule.Path, "/") {
+				v.add(fmt.Sprintf("zipkin.rules[%d].path", i), "必须以 / 开头: %q", rule.Path)
+			}
+		}
+	case "otel":
+		if c.Tracing.OTel.Exporter == "otlp" {
+			v.url("tracing.otel.endpoint", c.Tracing.OTel.Endpoint)
+		}
+		if c.Tracing.OTel.ServiceName == "" {
+			v.add("tracing.otel.service_name", "启用 OpenTelemetry 时不能为空")
+		}
+	}
+
+	// CORS
+	for i, origin := range c.CORS.AllowOrigins {
+		if origin != "*" {
+			v.url(fmt.Sprintf("cors.allow_origins[%d]", i), origin)
+		}
+	}
+
+	// Upstreams
+	upstreams := make(map[string]bool, len(c.Upstreams))
+	for i, upstream := range c.Upstreams {
+		path := fmt.Sprintf("upstreams[%d]", i)
+		switch {
+		case upstream.Name == "":
+			v.add(path+".name", "不能为空")
+		case upstreams[upstream.Name]:
+			v.add(path+".name", "上游名称重复: %q", upstream.Name)
+		}
+		upstreams[upstream.Name] = true
+		if upstream.BaseURL != "" {
+			v.url(path+".base_url", upstream.BaseURL)
+		}
+	}
+
+	// RateLimit
+	if c.RateLimit.Enabled {
+		if c.RateLimit.RequestsPerSecond <= 0 {
+			v.add("rate_limit.requests_per_second", "必须大于 0: %g", c.RateLimit.RequestsPerSecond)
+		}
+		if c.RateLimit.Burst < 1 {
+			v.add("rate_limit.burst", "必须大于等于 1: %d", c.RateLimit.Burst)
+		}
+	}
+
+	// Reload
+	if c.Reload.Enabled && c.Reload.Interval <= 0 {
+		v.add("reload.interval", "必须大于 0: %d", c.Reload.Interval)
+	}
+
+	// Shutdown
+	if c.Shutdown.CancelGrace >= c.Shutdown.HTTP {
+		v.add("shutdown.cancel_grace", "必须小于 http (%d >= %d)", c.Shutdown.CancelGrace, c.Shutdown.HTTP)
+	}
+	if budget := c.Shutdown.PreStopDelay + c.Shutdown.HTTP + c.Shutdown.Metrics + c.Shutdown.Workers +
+		c.Shutdown.Database + c.Shutdown.Tracing; budget > c.Shutdown.Timeout {
+		v.add("shutdown.timeout", "不能小于各阶段之和 pre_stop_delay+http+metrics+workers+database+tracing (%d < %d)",
+			c.Shutdown.Timeout, budget)
+	}
+
+	// 生产环境的额外安全约束
+	if c.IsProduction() {
+		if c.HasPlaceholderJWTSecret() {
+			v.add("jwt.secret", "生产环境禁止使用默认密钥")
+		}
+		if c.Database.SSLMode == "disable" {
+			v.add("database.sslmode", "生产环境禁止使用 disable")
+		}
+		if c.App.Debug {
+			v.add("app.debug", "生产环境禁止开启 debug")
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+	return nil
+}
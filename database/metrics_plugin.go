@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-web-template/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const metricsGormStartKey = "metrics:start"
+
+// 数据库查询指标，按操作类型（create/query/update/delete/row/raw）和表名打标签
+var (
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "数据库操作耗时（秒）",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"operation", "table"})
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "数据库操作失败次数（不含记录不存在）",
+	}, []string{"operation", "table"})
+	slowQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "耗时超过慢查询阈值的数据库操作次数",
+	}, []string{"operation", "table"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(queryDuration, queryErrors, slowQueries)
+}
+
+// registerPoolCollector 导出连接池状态（sql.DBStats）指标
+//
+// 包括最大/当前打开连接数、使用中与空闲连接数、等待次数与等待时长，
+// 以及因 max_idle_conns、空闲超时、conn_max_lifetime 关闭的连接数。
+func registerPoolCollector(sqlDB *sql.DB, dbName string) error {
+	return metrics.Registry.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+// MetricsPlugin 数据库查询指标插件，与 TracingPlugin 并列注册
+type MetricsPlugin struct {
+	// SlowThreshold 慢查询阈值，0 表示不统计慢查询；慢查询日志（含SQL）由 GormZapWriter 输出
+	SlowThreshold time.Duration
+}
+
+// Name 插件名称
+func (m *MetricsPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize 初始化插件
+func (m *MetricsPlugin) Initialize(db *gorm.DB) error {
+	// 注册回调，操作类型由注册的回调决定，而不是从SQL推断
+	db.Callback().Create().Before("gorm:create").Register("metrics:before_create", m.before)
+	db.Callback().Create().After("gorm:create").Register("metrics:after_create", m.after("create"))
+
+	db.Callback().Query().Before("gorm:query").Register("metrics:before_query", m.before)
+	db.Callback().Query().After("gorm:query").Register("metrics:after_query", m.after("query"))
+
+	db.Callback().Update().Before("gorm:update").Register("metrics:before_update", m.before)
+	db.Callback().Update().After("gorm:update").Register("metrics:after_update", m.after("update"))
+
+	db.Callback().Delete().Before("gorm:delete").Register("metrics:before_delete", m.before)
+	db.Callback().Delete().After("gorm:delete").Register("metrics:after_delete", m.after("delete"))
+
+	db.Callback().Row().Before("gorm:row").Register("metrics:before_row", m.before)
+	db.Callback().Row().After("gorm:row").Register("metrics:after_row", m.after("row"))
+
+	db.Callback().Raw().Before("gorm:raw").Register("metrics:before_raw", m.before)
+	db.Callback().Raw().After("gorm:raw").Register("metrics:after_raw", m.after("raw"))
+
+	return nil
+}
+
+// before 在操作前记录开始时间
+func (m *MetricsPlugin) before(db *gorm.DB) {
+	db.InstanceSet(metricsGormStartKey, time.Now())
+}
+
+// after 在操作后记录耗时、错误与慢查询
+func (m *MetricsPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		val, exists := db.InstanceGet(metricsGormStartKey)
+		if !exists {
+			return
+		}
+		start, ok := val.(time.Time)
+		if !ok {
+			return
+		}
+
+		elapsed := time.Since(start)
+		table := db.Statement.Table
+
+		queryDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			queryErrors.WithLabelValues(operation, table).Inc()
+		}
+		if m.SlowThreshold > 0 && elapsed > m.SlowThreshold {
+			slowQueries.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
@@ -2,12 +2,15 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"go-web-template/config"
+	"go-web-template/middlewares"
+	"go-web-template/tracing"
 
-	"github.com/openzipkin/zipkin-go"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -22,20 +25,17 @@ func Init(cfg *config.DatabaseConfig, log *zap.Logger) error {
 }
 
 // InitWithTracer 初始化带追踪的数据库连接
-func InitWithTracer(cfg *config.DatabaseConfig, log *zap.Logger, tracer *zipkin.Tracer) error {
-	// 构建DSN
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
+func InitWithTracer(cfg *config.DatabaseConfig, log *zap.Logger, tracer tracing.Tracer) error {
+	// 构建DSN（密码等字段已在加载配置时完成密钥引用解析）
+	dsn := cfg.DSN()
 
 	// 配置GORM日志
-	gormLogger := logger.New(
-		&GormZapWriter{Logger: log},
-		logger.Config{
-			SlowThreshold: time.Second,
-			LogLevel:      logger.Info,
-			Colorful:      false,
-		},
-	)
+	slowThreshold := time.Duration(cfg.SlowThreshold) * time.Millisecond
+	gormLogger := &GormZapWriter{
+		Logger:        log,
+		SlowThreshold: slowThreshold,
+		LogLevel:      logger.Info,
+	}
 
 	// 打开数据库连接
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
@@ -52,9 +52,7 @@ func InitWithTracer(cfg *config.DatabaseConfig, log *zap.Logger, tracer *zipkin.
 	}
 
 	// 配置连接池
-	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
-	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+	applyPoolConfig(sqlDB, cfg)
 
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -64,17 +62,40 @@ func InitWithTracer(cfg *config.DatabaseConfig, log *zap.Logger, tracer *zipkin.
 		return fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 
+	// 添加查询指标插件并导出连接池状态
+	if err := db.Use(&MetricsPlugin{SlowThreshold: slowThreshold}); err != nil {
+		log.Warn("添加数据库指标插件失败", zap.Error(err))
+	}
+	if err := registerPoolCollector(sqlDB, cfg.DBName); err != nil {
+		log.Warn("注册数据库连接池指标失败", zap.Error(err))
+	}
+
 	// 如果有tracer，添加追踪插件
 	if tracer != nil {
-		if err := db.Use(&ZipkinPlugin{tracer: tracer}); err != nil {
-			log.Warn("添加Zipkin追踪插件失败", zap.Error(err))
+		if err := db.Use(NewTracingPlugin(tracer, cfg)); err != nil {
+			log.Warn("添加链路追踪插件失败", zap.Error(err))
 		} else {
-			log.Info("Zipkin数据库追踪插件已启用")
+			log.Info("数据库链路追踪插件已启用")
 		}
 	}
 
 	DB = db
 
+	// 配置热加载时同步调整连接池参数
+	config.OnChange(func(prev, next *config.Config) {
+		if prev.Database.MaxIdleConns == next.Database.MaxIdleConns &&
+			prev.Database.MaxOpenConns == next.Database.MaxOpenConns &&
+			prev.Database.ConnMaxLifetime == next.Database.ConnMaxLifetime {
+			return
+		}
+		applyPoolConfig(sqlDB, &next.Database)
+		log.Info("数据库连接池配置已更新",
+			zap.Int("max_idle_conns", next.Database.MaxIdleConns),
+			zap.Int("max_open_conns", next.Database.MaxOpenConns),
+			zap.Int("conn_max_lifetime", next.Database.ConnMaxLifetime),
+		)
+	})
+
 	log.Info("数据库连接初始化成功",
 		zap.String("host", cfg.Host),
 		zap.Int("port", cfg.Port),
@@ -86,6 +107,13 @@ func InitWithTracer(cfg *config.DatabaseConfig, log *zap.Logger, tracer *zipkin.
 	return nil
 }
 
+// applyPoolConfig 设置连接池参数
+func applyPoolConfig(sqlDB *sql.DB, cfg *config.DatabaseConfig) {
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+}
+
 // Close 关闭数据库连接
 func Close() error {
 	if DB != nil {
@@ -104,10 +132,74 @@ func GetDB() *gorm.DB {
 }
 
 // GormZapWriter GORM的Zap日志写入器
+//
+// 实现 gorm logger.Interface。通过 DB.WithContext(ctx) 执行的SQL优先使用上下文中的请求logger，
+// 日志会携带 request_id、trace_id、user_id 等字段。
 type GormZapWriter struct {
-	Logger *zap.Logger
+	Logger        *zap.Logger
+	SlowThreshold time.Duration
+	LogLevel      logger.LogLevel
+}
+
+// LogMode 设置日志级别
+func (g *GormZapWriter) LogMode(level logger.LogLevel) logger.Interface {
+	w := *g
+	w.LogLevel = level
+	return &w
 }
 
-func (g *GormZapWriter) Printf(format string, args ...interface{}) {
-	g.Logger.Info(fmt.Sprintf(format, args...))
+// Info 记录info日志
+func (g *GormZapWriter) Info(ctx context.Context, msg string, args ...interface{}) {
+	if g.LogLevel >= logger.Info {
+		g.logger(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn 记录warn日志
+func (g *GormZapWriter) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if g.LogLevel >= logger.Warn {
+		g.logger(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error 记录error日志
+func (g *GormZapWriter) Error(ctx context.Context, msg string, args ...interface{}) {
+	if g.LogLevel >= logger.Error {
+		g.logger(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace 记录SQL执行日志
+func (g *GormZapWriter) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.LogLevel <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+	}
+
+	switch {
+	case err != nil && g.LogLevel >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		g.logger(ctx).Error("SQL执行失败", append(fields, zap.Error(err))...)
+	case g.SlowThreshold != 0 && elapsed > g.SlowThreshold && g.LogLevel >= logger.Warn:
+		g.logger(ctx).Warn("慢查询", append(fields, zap.Duration("threshold", g.SlowThreshold))...)
+	case g.LogLevel >= logger.Info:
+		g.logger(ctx).Debug("SQL执行", fields...)
+	}
+}
+
+// logger 获取上下文中的请求logger，没有时使用初始化时传入的logger
+//
+// 日志按 gorm 模块级别过滤，可通过管理接口单独开启 SQL 的 debug 日志。
+func (g *GormZapWriter) logger(ctx context.Context) *zap.Logger {
+	l := g.Logger
+	if ctxLogger, ok := middlewares.ContextLogger(ctx); ok {
+		l = ctxLogger
+	}
+	return middlewares.ForModule(l, middlewares.ModuleGorm)
 }
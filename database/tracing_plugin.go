@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go-web-template/config"
+	"go-web-template/tracing"
+
+	"gorm.io/gorm"
+)
+
+const tracingGormSpanKey = "tracing:span"
+
+// dbSystem 数据库类型，对应 span 的 db.system 标签
+const dbSystem = "postgresql"
+
+// TracingPlugin 链路追踪插件
+//
+// 每个数据库操作生成一个 client span，按回调类型（create/query/update/delete/row/raw）和表名命名，
+// 例如 db:query:users。
+type TracingPlugin struct {
+	tracer   tracing.Tracer
+	dbName   string
+	remote   tracing.Endpoint
+	peerTags map[string]string
+	tracing  config.DatabaseTracingConfig
+}
+
+// NewTracingPlugin 创建链路追踪插件
+func NewTracingPlugin(tracer tracing.Tracer, cfg *config.DatabaseConfig) *TracingPlugin {
+	return &TracingPlugin{
+		tracer: tracer,
+		dbName: cfg.DBName,
+		remote: tracing.Endpoint{ServiceName: dbSystem, Host: cfg.Host, Port: cfg.Port},
+		peerTags: map[string]string{
+			"net.peer.name": cfg.Host,
+			"net.peer.port": strconv.Itoa(cfg.Port),
+		},
+		tracing: cfg.Tracing,
+	}
+}
+
+// Name 插件名称
+func (p *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 初始化插件
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	// 注册回调，操作类型由注册的回调决定（before 回调执行时SQL尚未生成，无法从SQL推断）
+	db.Callback().Create().Before("gorm:create").Register("tracing:before_create", p.before("create"))
+	db.Callback().Create().After("gorm:create").Register("tracing:after_create", p.after)
+
+	db.Callback().Query().Before("gorm:query").Register("tracing:before_query", p.before("query"))
+	db.Callback().Query().After("gorm:query").Register("tracing:after_query", p.after)
+
+	db.Callback().Update().Before("gorm:update").Register("tracing:before_update", p.before("update"))
+	db.Callback().Update().After("gorm:update").Register("tracing:after_update", p.after)
+
+	db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", p.before("delete"))
+	db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", p.after)
+
+	db.Callback().Row().Before("gorm:row").Register("tracing:before_row", p.before("row"))
+	db.Callback().Row().After("gorm:row").Register("tracing:after_row", p.after)
+
+	db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("raw"))
+	db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", p.after)
+
+	return nil
+}
+
+// before 返回在操作前创建span的回调
+func (p *TracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if p.tracer == nil {
+			return
+		}
+
+		// 从上下文获取parent span
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		// 创建span，表名在执行回调前已由模型解析得到，Raw/Exec 操作没有表名
+		table := db.Statement.Table
+		operationName := "db:" + operation
+		if table != "" {
+			operationName += ":" + table
+		}
+
+		ctx, span := p.tracer.StartSpan(ctx, operationName,
+			tracing.WithKind(tracing.KindClient),
+			tracing.WithRemoteEndpoint(p.remote),
+			tracing.WithTags(p.peerTags),
+		)
+
+		// 设置标签
+		span.Tag("db.system", dbSystem)
+		span.Tag("db.name", p.dbName)
+		span.Tag("db.operation", operation)
+		if table != "" {
+			span.Tag("db.sql.table", table)
+		}
+
+		// 将span存储到context中
+		db.Statement.Context = ctx
+
+		// 将span存储到实例变量中，以便在after回调中使用
+		db.InstanceSet(tracingGormSpanKey, span)
+	}
+}
+
+// after 在操作后的回调
+func (p *TracingPlugin) after(db *gorm.DB) {
+	// 获取span
+	val, exists := db.InstanceGet(tracingGormSpanKey)
+	if !exists {
+		return
+	}
+
+	span, ok := val.(tracing.Span)
+	if !ok {
+		return
+	}
+
+	// 设置SQL语句，默认只记录带占位符的SQL，开启 full_sql 后内联绑定变量
+	if sql := db.Statement.SQL.String(); sql != "" {
+		if p.tracing.FullSQL {
+			sql = db.Dialector.Explain(sql, db.Statement.Vars...)
+		}
+		span.Tag("db.statement", sql)
+	}
+
+	// 记录受影响的行数
+	if p.tracing.RowsAffected && db.Statement.RowsAffected >= 0 {
+		span.Tag("db.rows_affected", strconv.FormatInt(db.Statement.RowsAffected, 10))
+	}
+
+	// 如果有错误，记录错误信息（记录不存在不视为错误）
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.SetError(db.Error.Error())
+	}
+
+	// 完成span
+	span.Finish()
+}
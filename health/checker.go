@@ -0,0 +1,197 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 未配置时使用的超时
+const (
+	defaultTimeout      = 3 * time.Second
+	defaultCheckTimeout = time.Second
+)
+
+// newHealthChecker 创建新的健康检查器
+func newHealthChecker(serviceName, version string, logger *zap.Logger) *HealthChecker {
+	return &HealthChecker{
+		serviceName:  serviceName,
+		version:      version,
+		logger:       logger,
+		checks:       make([]Checker, 0),
+		timeout:      defaultTimeout,
+		checkTimeout: defaultCheckTimeout,
+	}
+}
+
+// executeCheck 在超时限制内执行单个检查，panic 和超时均视为检查失败
+//
+// 检查函数未在超时内返回时直接返回超时结果，检查函数所在的 goroutine 在其自行返回后退出。
+// 单项超时与外层上下文结束（总超时到期或调用方取消）分别给出不同的失败原因。
+func (hc *HealthChecker) executeCheck(parent context.Context, checker Checker, timeout time.Duration) HealthCheck {
+	if checker.Timeout > 0 {
+		timeout = checker.Timeout
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	done := make(chan HealthCheck, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				hc.logger.Error("健康检查执行时发生panic",
+					zap.String("check", checker.Name),
+					zap.Any("panic", r),
+				)
+				done <- HealthCheck{
+					Status:  HealthStatusUnhealthy,
+					Message: fmt.Sprintf("检查执行时发生panic: %v", r),
+				}
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	var check HealthCheck
+	select {
+	case check = <-done:
+	case <-ctx.Done():
+		message := fmt.Sprintf("检查超时（%s）", timeout)
+		switch err := parent.Err(); {
+		case errors.Is(err, context.Canceled):
+			message = "调用方已取消，检查未完成"
+		case errors.Is(err, context.DeadlineExceeded):
+			message = "健康检查总超时到期，检查未完成"
+		}
+		check = HealthCheck{
+			Status:  HealthStatusUnhealthy,
+			Message: message,
+		}
+	}
+
+	if check.Name == "" {
+		check.Name = checker.Name
+	}
+	check.Duration = time.Since(startTime).String()
+	return check
+}
+
+// AddCheck 添加健康检查项
+func (hc *HealthChecker) AddCheck(checker Checker) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.checks = append(hc.checks, checker)
+}
+
+// configure 设置超时与缓存时间，同时清空缓存
+func (hc *HealthChecker) configure(timeout, checkTimeout, cacheTTL time.Duration) {
+	hc.mu.Lock()
+	hc.timeout = timeout
+	hc.checkTimeout = checkTimeout
+	hc.cacheTTL = cacheTTL
+	hc.mu.Unlock()
+
+	hc.cacheMu.Lock()
+	hc.cached = nil
+	hc.cacheMu.Unlock()
+}
+
+// Check 执行健康检查，缓存时间内直接返回上次的结果
+func (hc *HealthChecker) Check(ctx context.Context) HealthResponse {
+	hc.mu.RLock()
+	cacheTTL := hc.cacheTTL
+	hc.mu.RUnlock()
+
+	hc.cacheMu.Lock()
+	defer hc.cacheMu.Unlock()
+
+	if hc.cached != nil && time.Since(hc.cachedAt) < cacheTTL {
+		response := *hc.cached
+		response.Cached = true
+		return response
+	}
+
+	// 缓存的结果会返回给其他调用方，不受本次调用方取消的影响，只受总超时限制
+	if cacheTTL > 0 {
+		ctx = context.WithoutCancel(ctx)
+	}
+	response := hc.run(ctx)
+	if cacheTTL > 0 {
+		hc.cached = &response
+		hc.cachedAt = time.Now()
+	}
+	return response
+}
+
+// run 在总超时内并发执行所有检查项
+func (hc *HealthChecker) run(ctx context.Context) HealthResponse {
+	startTime := time.Now()
+
+	hc.mu.RLock()
+	checks := append([]Checker(nil), hc.checks...)
+	timeout, checkTimeout := hc.timeout, hc.checkTimeout
+	version := hc.version
+	hc.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 执行所有检查
+	results := make([]HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, checker := range checks {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = hc.executeCheck(ctx, checker, checkTimeout)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	response := HealthResponse{
+		Status:    HealthStatusHealthy,
+		Version:   version,
+		Timestamp: time.Now(),
+		Checks:    results,
+	}
+
+	// 根据检查结果更新整体状态，非关键检查项失败只降级
+	for i, check := range results {
+		switch {
+		case check.Status == HealthStatusUnhealthy && checks[i].Critical:
+			response.Status = HealthStatusUnhealthy
+		case check.Status != HealthStatusHealthy && response.Status == HealthStatusHealthy:
+			response.Status = HealthStatusDegraded
+		}
+	}
+
+	duration := time.Since(startTime)
+	hc.logger.Debug("健康检查执行完成",
+		zap.String("service", hc.serviceName),
+		zap.String("status", string(response.Status)),
+		zap.Duration("duration", duration),
+		zap.Int("checks_count", len(response.Checks)),
+	)
+
+	return response
+}
+
+// status 返回不执行检查项、直接由单个状态构成的响应
+func (hc *HealthChecker) status(status HealthStatus, name, message string) HealthResponse {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	return HealthResponse{
+		Status:    status,
+		Version:   hc.version,
+		Timestamp: time.Now(),
+		Checks: []HealthCheck{
+			{Name: name, Status: status, Message: message},
+		},
+	}
+}
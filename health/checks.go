@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+
+	"go-web-template/database"
+	"go-web-template/utils"
+
+	"go.uber.org/zap"
+)
+
+// DatabaseCheck 数据库健康检查，通过 PingContext 确认连接可用，属于关键检查项
+func DatabaseCheck() Checker {
+	return Checker{Name: "database", Critical: true, Check: func(ctx context.Context) HealthCheck {
+		check := HealthCheck{Name: "database"}
+		if database.DB == nil {
+			check.Status = HealthStatusUnhealthy
+			check.Message = "数据库未初始化"
+			return check
+		}
+
+		sqlDB, err := database.DB.DB()
+		if err != nil {
+			check.Status = HealthStatusUnhealthy
+			check.Message = "获取数据库连接失败: " + err.Error()
+			return check
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			check.Status = HealthStatusUnhealthy
+			check.Message = "数据库连接失败: " + err.Error()
+			return check
+		}
+
+		check.Status = HealthStatusHealthy
+		check.Message = "数据库连接正常"
+		return check
+	}}
+}
+
+// ZipkinCheck Zipkin 可达性检查
+//
+// 追踪数据丢失不影响业务请求，因此属于非关键检查项，Zipkin 不可达时整体状态为 degraded。
+func ZipkinCheck(endpoint string, logger *zap.Logger) Checker {
+	zipkinChecker := utils.NewZipkinHealthChecker(endpoint, logger)
+	return Checker{Name: "zipkin", Check: func(ctx context.Context) HealthCheck {
+		check := HealthCheck{Name: "zipkin"}
+		if err := zipkinChecker.Ping(ctx); err != nil {
+			check.Status = HealthStatusUnhealthy
+			check.Message = "Zipkin服务不可达: " + err.Error()
+			return check
+		}
+
+		check.Status = HealthStatusHealthy
+		check.Message = "Zipkin服务连接正常"
+		return check
+	}}
+}
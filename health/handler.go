@@ -0,0 +1,30 @@
+package health
+
+import (
+	"net/http"
+
+	"go-web-template/middlewares"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Handler 返回指定探针的HTTP处理函数
+//
+// 状态为 healthy 或 degraded 时返回 200，unhealthy 时返回 503。
+func Handler(probe Probe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := Run(c.Request.Context(), probe)
+
+		code := http.StatusOK
+		if response.Status == HealthStatusUnhealthy {
+			code = http.StatusServiceUnavailable
+			middlewares.LoggerFrom(c.Request.Context()).Warn("健康检查未通过",
+				zap.String("probe", string(probe)),
+				zap.Any("checks", response.Checks),
+			)
+		}
+
+		c.JSON(code, response)
+	}
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go-web-template/config"
+
+	"go.uber.org/zap"
+)
+
+// 各探针的检查器，模块可通过 AddCheck 注册检查项
+var checkers = map[Probe]*HealthChecker{
+	ProbeLiveness:  newHealthChecker("", "", zap.L()),
+	ProbeReadiness: newHealthChecker("", "", zap.L()),
+	ProbeStartup:   newHealthChecker("", "", zap.L()),
+}
+
+var (
+	// started 启动完成后置为 true，之前 startup 探针返回 503
+	started atomic.Bool
+	// shuttingDown 开始优雅关闭后置为 true，之后 readiness 探针返回 503
+	shuttingDown atomic.Bool
+)
+
+// Init 设置服务名称、版本、日志以及超时与缓存配置，超时与缓存配置支持热加载
+func Init(cfg *config.HealthConfig, serviceName, version string, logger *zap.Logger) {
+	for _, hc := range checkers {
+		hc.mu.Lock()
+		hc.serviceName = serviceName
+		hc.version = version
+		hc.logger = logger
+		hc.mu.Unlock()
+	}
+	configure(cfg)
+
+	config.OnChange(func(prev, next *config.Config) {
+		if prev.Health == next.Health {
+			return
+		}
+		configure(&next.Health)
+		logger.Info("健康检查配置已更新",
+			zap.Int("timeout_ms", next.Health.Timeout),
+			zap.Int("check_timeout_ms", next.Health.CheckTimeout),
+			zap.Int("cache_ttl_ms", next.Health.CacheTTL),
+		)
+	})
+}
+
+// configure 将超时与缓存配置应用到所有探针
+func configure(cfg *config.HealthConfig) {
+	for _, hc := range checkers {
+		hc.configure(
+			time.Duration(cfg.Timeout)*time.Millisecond,
+			time.Duration(cfg.CheckTimeout)*time.Millisecond,
+			time.Duration(cfg.CacheTTL)*time.Millisecond,
+		)
+	}
+}
+
+// AddCheck 为指定探针注册检查项
+//
+// liveness 只应包含进程自身的检查，依赖服务（数据库等）的检查应注册到 readiness，
+// 避免依赖故障导致容器被反复重启。
+func AddCheck(probe Probe, checker Checker) {
+	if hc, ok := checkers[probe]; ok {
+		hc.AddCheck(checker)
+	}
+}
+
+// Run 执行指定探针的检查
+//
+// startup 探针在 MarkStarted 之前、readiness 探针在 MarkShuttingDown 之后直接返回 unhealthy。
+func Run(ctx context.Context, probe Probe) HealthResponse {
+	hc := checkers[probe]
+	switch {
+	case probe == ProbeStartup && !started.Load():
+		return hc.status(HealthStatusUnhealthy, "lifecycle", "应用正在启动")
+	case probe == ProbeReadiness && shuttingDown.Load():
+		return hc.status(HealthStatusUnhealthy, "lifecycle", "应用正在关闭")
+	}
+	return hc.Check(ctx)
+}
+
+// MarkStarted 标记应用启动完成
+func MarkStarted() {
+	started.Store(true)
+}
+
+// MarkShuttingDown 标记应用开始关闭，readiness 探针随即返回 503，
+// 使负载均衡在连接关闭前摘除流量
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// ShuttingDown 应用是否正在关闭
+func ShuttingDown() bool {
+	return shuttingDown.Load()
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-web-template/buildinfo"
+
+	"go.uber.org/zap"
+)
+
+// HealthStatus 健康状态枚举
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "healthy"
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+	HealthStatusDegraded  HealthStatus = "degraded"
+)
+
+// Probe 探针类型，对应 Kubernetes 的 liveness、readiness、startup 探针
+type Probe string
+
+const (
+	ProbeLiveness  Probe = "live"
+	ProbeReadiness Probe = "ready"
+	ProbeStartup   Probe = "startup"
+)
+
+// HealthCheck 健康检查项
+type HealthCheck struct {
+	Name     string       `json:"name"`
+	Status   HealthStatus `json:"status"`
+	Message  string       `json:"message,omitempty"`
+	Duration string       `json:"duration,omitempty"`
+}
+
+// HealthResponse 健康检查响应
+type HealthResponse struct {
+	Status    HealthStatus    `json:"status"`
+	Version   string          `json:"version"`
+	Timestamp time.Time       `json:"timestamp"`
+	Cached    bool            `json:"cached,omitempty"`
+	Checks    []HealthCheck   `json:"checks"`
+	Build     *buildinfo.Info `json:"build,omitempty"`
+}
+
+// CheckFunc 健康检查函数，应在 ctx 取消后尽快返回
+type CheckFunc func(ctx context.Context) HealthCheck
+
+// Checker 注册的检查项
+type Checker struct {
+	Name     string        // 检查项名称，检查函数未返回名称（如发生panic或超时）时使用
+	Check    CheckFunc     // 检查函数
+	Timeout  time.Duration // 单个检查的超时，0 表示使用 health.check_timeout
+	Critical bool          // 关键检查项失败时整体为 unhealthy，非关键检查项失败时整体为 degraded
+}
+
+// HealthChecker 健康检查器
+type HealthChecker struct {
+	serviceName  string
+	version      string
+	logger       *zap.Logger
+	checks       []Checker
+	timeout      time.Duration
+	checkTimeout time.Duration
+	cacheTTL     time.Duration
+	mu           sync.RWMutex
+
+	// 缓存最近一次检查结果，cacheMu 同时保证同一时间只有一次检查在执行
+	cacheMu  sync.Mutex
+	cached   *HealthResponse
+	cachedAt time.Time
+}
@@ -0,0 +1,206 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-web-template/config"
+	"go-web-template/middlewares"
+
+	"go.uber.org/zap"
+)
+
+// defaultRetryBackoff 未配置 retry_backoff 时首次重试前的等待时间
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// IdempotencyKeyHeader 幂等键请求头，携带该头部的非幂等请求（如 POST）也允许重试
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// baseTransport 所有客户端共享的底层传输层，复用连接池
+var baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// Client 访问某个上游服务的HTTP客户端
+//
+// 每次尝试都会创建 client 类型的 span 并注入 B3 与 W3C traceparent 请求头，
+// 按上游和目标主机记录请求数、耗时和重试次数指标。
+type Client struct {
+	name    string
+	baseURL *url.URL
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	http    *http.Client
+}
+
+// New 根据上游配置创建HTTP客户端
+func New(cfg config.UpstreamConfig) (*Client, error) {
+	c := &Client{
+		name:    cfg.Name,
+		timeout: time.Duration(cfg.Timeout) * time.Millisecond,
+		retries: cfg.Retries,
+		backoff: time.Duration(cfg.RetryBackoff) * time.Millisecond,
+		http: &http.Client{
+			Transport: &tracingTransport{upstream: cfg.Name, next: baseTransport},
+		},
+	}
+	if c.backoff <= 0 {
+		c.backoff = defaultRetryBackoff
+	}
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("上游服务 %q 的 base_url 无效: %w", cfg.Name, err)
+		}
+		c.baseURL = u
+	}
+	return c, nil
+}
+
+// Name 返回上游名称
+func (c *Client) Name() string {
+	return c.name
+}
+
+// NewRequest 创建请求，path 相对于上游的 base_url 解析，也可以是完整的URL
+//
+// ctx 中的 span 作为出站 span 的父 span，通常传入 c.Request.Context()。
+func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	target := path
+	if c.baseURL != nil {
+		ref, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("请求路径无效: %w", err)
+		}
+		target = c.baseURL.ResolveReference(ref).String()
+	}
+	return http.NewRequestWithContext(ctx, method, target, body)
+}
+
+// Get 发送GET请求
+func (c *Client) Get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do 发送请求
+//
+// 超时覆盖整个调用（包括重试与读取响应体），调用方必须关闭响应体。
+// 幂等请求遇到网络错误或 502/503/504 时按指数退避重试。
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		req = req.WithContext(ctx)
+	}
+
+	retries := c.retries
+	if !retryable(req) {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.http.Do(req)
+		if attempt >= retries || !shouldRetry(resp, err) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// 超时上下文需要在读取完响应体后才能释放
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		wait := c.backoff<<attempt + rand.N(c.backoff/2+1)
+		fields := []zap.Field{
+			zap.String("upstream", c.name),
+			zap.String("method", req.Method),
+			zap.String("url", req.URL.Redacted()),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", wait),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.Int("status_code", resp.StatusCode))
+			// 读取并关闭响应体以便复用连接
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		middlewares.LoggerFrom(ctx).Warn("上游请求失败，准备重试", fields...)
+		clientRetries.WithLabelValues(c.name, req.URL.Host).Inc()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			cancel()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req, err = rewind(req); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+}
+
+// retryable 判断请求是否允许重试：幂等方法或携带幂等键，且请求体可以重放
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// shouldRetry 网络错误或网关类错误（502/503/504）时重试，上下文取消或超时不重试
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewind 复制请求并重置请求体，用于重试
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("重置请求体失败: %w", err)
+		}
+		next.Body = body
+	}
+	return next, nil
+}
+
+// cancelBody 关闭响应体时释放超时上下文
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
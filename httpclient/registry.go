@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"fmt"
+	"sync"
+
+	"go-web-template/config"
+)
+
+var (
+	clientsMu sync.RWMutex
+	clients   = map[string]*Client{}
+)
+
+// Init 根据 upstreams 配置创建各上游的客户端
+func Init(upstreams []config.UpstreamConfig) error {
+	created := make(map[string]*Client, len(upstreams))
+	for _, upstream := range upstreams {
+		client, err := New(upstream)
+		if err != nil {
+			return err
+		}
+		created[upstream.Name] = client
+	}
+
+	clientsMu.Lock()
+	clients = created
+	clientsMu.Unlock()
+	return nil
+}
+
+// Get 获取指定上游的客户端
+func Get(name string) (*Client, error) {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	client, ok := clients[name]
+	if !ok {
+		return nil, fmt.Errorf("未配置的上游服务 %q", name)
+	}
+	return client, nil
+}
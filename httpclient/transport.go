@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-web-template/metrics"
+	"go-web-template/tracing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	clientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_requests_total",
+		Help: "出站HTTP请求总数（每次重试单独计数），status 为状态码分类或 error",
+	}, []string{"upstream", "host", "method", "status"})
+	clientDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_request_duration_seconds",
+		Help:    "出站HTTP请求耗时（秒），从发出请求到收到响应头",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"upstream", "host", "method"})
+	clientRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_retries_total",
+		Help: "出站HTTP请求重试次数",
+	}, []string{"upstream", "host"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(clientRequests, clientDuration, clientRetries)
+}
+
+// tracingTransport 为每次请求创建 client span、注入追踪请求头并记录指标
+type tracingTransport struct {
+	upstream string
+	next     http.RoundTripper
+}
+
+// RoundTrip 发送单次请求
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	start := time.Now()
+
+	_, span := tracing.StartSpan(req.Context(), req.Method+" "+t.upstream,
+		tracing.WithKind(tracing.KindClient),
+		tracing.WithRemoteEndpoint(tracing.Endpoint{ServiceName: t.upstream, Host: req.URL.Hostname()}),
+	)
+	if span != nil {
+		span.Tag("http.method", req.Method)
+		span.Tag("http.url", req.URL.Redacted())
+		span.Tag("net.peer.name", host)
+
+		// RoundTripper 不能修改传入的请求，注入请求头前先复制
+		req = req.Clone(req.Context())
+		tracing.Inject(span, req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	status := "error"
+	if err == nil {
+		status = metrics.StatusClass(resp.StatusCode)
+	}
+	clientRequests.WithLabelValues(t.upstream, host, req.Method, status).Inc()
+	clientDuration.WithLabelValues(t.upstream, host, req.Method).Observe(time.Since(start).Seconds())
+
+	if span != nil {
+		if err != nil {
+			span.SetError(err.Error())
+		} else {
+			span.Tag("http.status_code", strconv.Itoa(resp.StatusCode))
+			if resp.StatusCode >= 500 {
+				span.SetError(resp.Status)
+			}
+		}
+		span.Finish()
+	}
+
+	return resp, err
+}
@@ -0,0 +1,80 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HTTPServer 作为组件运行的HTTP服务器
+//
+// 启动时同步监听端口，端口被占用等错误直接导致启动失败。停止时等待处理中的请求完成，
+// 停止超时到期前 CancelGrace 取消所有请求的上下文，使长连接请求（如长轮询、SSE）尽快结束，
+// 到期后强制关闭剩余的连接。
+type HTTPServer struct {
+	Name   string
+	Server *http.Server
+	Logger *zap.Logger
+
+	// CancelGrace 取消请求上下文后留给请求结束的时间
+	CancelGrace time.Duration
+	// OnCancel 取消请求上下文前调用，可用于输出仍在处理的请求
+	OnCancel func()
+	// OnDeadline 停止超时到期、强制关闭连接前调用
+	OnDeadline func()
+
+	cancel context.CancelFunc
+}
+
+// Start 监听端口并在后台处理请求
+func (s *HTTPServer) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Server.Addr)
+	if err != nil {
+		return fmt.Errorf("%s监听 %s 失败: %w", s.Name, s.Server.Addr, err)
+	}
+
+	// 请求的上下文派生自 baseCtx，停止时统一取消
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.Server.BaseContext = func(net.Listener) context.Context { return baseCtx }
+
+	s.Logger.Info(s.Name+"启动", zap.String("address", ln.Addr().String()))
+	go func() {
+		if err := s.Server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.Logger.Error(s.Name+"异常退出", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Stop 停止接收新请求并等待处理中的请求完成，超时后强制关闭连接
+func (s *HTTPServer) Stop(ctx context.Context) error {
+	defer s.cancel()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		timer := time.AfterFunc(time.Until(deadline)-s.CancelGrace, func() {
+			if s.OnCancel != nil {
+				s.OnCancel()
+			}
+			s.cancel()
+		})
+		defer timer.Stop()
+	}
+
+	err := s.Server.Shutdown(ctx)
+	if err == nil {
+		return nil
+	}
+	if s.OnDeadline != nil {
+		s.OnDeadline()
+	}
+	if closeErr := s.Server.Close(); closeErr != nil {
+		return errors.Join(err, closeErr)
+	}
+	return err
+}
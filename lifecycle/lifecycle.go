@@ -0,0 +1,227 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultStopTimeout 未通过 StopTimeout 指定时单个组件的停止超时
+const DefaultStopTimeout = 5 * time.Second
+
+// Component 由生命周期管理器启动和停止的组件
+type Component interface {
+	// Start 启动组件，返回前应完成初始化；需要后台运行的工作应另起 goroutine，
+	// ctx 在应用运行期间有效，开始关闭时取消
+	Start(ctx context.Context) error
+	// Stop 停止组件并释放资源，ctx 到期时应尽快返回
+	Stop(ctx context.Context) error
+}
+
+// Hooks 使用函数实现的组件，未设置的函数视为无需处理
+type Hooks struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Start 启动组件
+func (h Hooks) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart(ctx)
+}
+
+// Stop 停止组件
+func (h Hooks) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop(ctx)
+}
+
+// Option 注册组件的选项
+type Option func(*entry)
+
+// DependsOn 声明依赖的组件，依赖的组件先启动、后停止
+func DependsOn(names ...string) Option {
+	return func(e *entry) {
+		e.dependsOn = append(e.dependsOn, names...)
+	}
+}
+
+// StopTimeout 设置组件的停止超时，同时受整体关闭超时限制
+func StopTimeout(timeout time.Duration) Option {
+	return func(e *entry) {
+		e.stopTimeout = timeout
+	}
+}
+
+// entry 已注册的组件
+type entry struct {
+	name        string
+	component   Component
+	dependsOn   []string
+	stopTimeout time.Duration
+}
+
+// Manager 生命周期管理器，按依赖顺序启动组件，按相反顺序停止
+type Manager struct {
+	logger  *zap.Logger
+	entries []*entry
+	byName  map[string]*entry
+	started []*entry
+}
+
+// New 创建生命周期管理器
+func New(logger *zap.Logger) *Manager {
+	return &Manager{logger: logger, byName: make(map[string]*entry)}
+}
+
+// Add 注册组件，没有依赖关系的组件按注册顺序启动
+func (m *Manager) Add(name string, component Component, opts ...Option) {
+	e := &entry{name: name, component: component, stopTimeout: DefaultStopTimeout}
+	for _, opt := range opts {
+		opt(e)
+	}
+	m.entries = append(m.entries, e)
+	m.byName[name] = e
+}
+
+// order 按依赖关系排序组件，依赖不存在或存在循环依赖时返回错误
+func (m *Manager) order() ([]*entry, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*entry]int, len(m.entries))
+	ordered := make([]*entry, 0, len(m.entries))
+
+	var visit func(e *entry) error
+	visit = func(e *entry) error {
+		switch state[e] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("组件 %q 存在循环依赖", e.name)
+		}
+		state[e] = visiting
+		for _, name := range e.dependsOn {
+			dep, ok := m.byName[name]
+			if !ok {
+				return fmt.Errorf("组件 %q 依赖的组件 %q 未注册", e.name, name)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[e] = visited
+		ordered = append(ordered, e)
+		return nil
+	}
+
+	for _, e := range m.entries {
+		if err := visit(e); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Start 按依赖顺序启动所有组件，某个组件启动失败时停止已启动的组件并返回错误
+func (m *Manager) Start(ctx context.Context) error {
+	ordered, err := m.order()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range ordered {
+		start := time.Now()
+		if err := e.component.Start(ctx); err != nil {
+			startErr := fmt.Errorf("启动组件 %q 失败: %w", e.name, err)
+			if stopErr := m.Stop(context.Background()); stopErr != nil {
+				return errors.Join(startErr, stopErr)
+			}
+			return startErr
+		}
+		m.started = append(m.started, e)
+		m.logger.Info("组件已启动", zap.String("component", e.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	return nil
+}
+
+// Stop 按启动的相反顺序停止已启动的组件，每个组件的停止超时同时受 ctx 限制
+//
+// 某个组件停止失败或超时不影响其余组件的停止，返回所有错误。
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		e := m.started[i]
+		start := time.Now()
+
+		stopCtx, cancel := context.WithTimeout(ctx, e.stopTimeout)
+		err := e.component.Stop(stopCtx)
+		cancel()
+
+		if err != nil {
+			m.logger.Error("组件停止失败",
+				zap.String("component", e.name),
+				zap.Duration("elapsed", time.Since(start)),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("停止组件 %q 失败: %w", e.name, err))
+			continue
+		}
+		m.logger.Info("组件已停止", zap.String("component", e.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	m.started = nil
+	return errors.Join(errs...)
+}
+
+// Run 启动所有组件并等待 SIGINT/SIGTERM 或 ctx 取消，然后在 shutdownTimeout 内停止所有组件
+//
+// 关闭过程中再次收到信号时立即退出进程。
+func (m *Manager) Run(ctx context.Context, shutdownTimeout time.Duration) error {
+	// 在启动前注册信号，启动过程中收到的信号同样触发关闭
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := m.Start(runCtx); err != nil {
+		return err
+	}
+
+	select {
+	case s := <-sig:
+		m.logger.Info("接收到关闭信号，开始优雅关闭", zap.String("signal", s.String()))
+	case <-ctx.Done():
+		m.logger.Info("上下文已取消，开始优雅关闭")
+	}
+	cancel()
+
+	// 再次收到信号时不再等待
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case s := <-sig:
+			m.logger.Warn("再次接收到关闭信号，强制退出", zap.String("signal", s.String()))
+			_ = m.logger.Sync()
+			os.Exit(1)
+		case <-stopped:
+		}
+	}()
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer stopCancel()
+	return m.Stop(stopCtx)
+}
@@ -0,0 +1,95 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Workers 后台任务组件，停止时取消任务的上下文并等待任务退出
+//
+// 任务函数应在 ctx 取消后尽快返回，超时仍未退出的任务记录在停止错误与日志中。
+type Workers struct {
+	logger *zap.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// NewWorkers 创建后台任务组件
+func NewWorkers(logger *zap.Logger) *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{logger: logger, ctx: ctx, cancel: cancel, running: make(map[string]int)}
+}
+
+// Go 在后台运行任务，ctx 在组件停止时取消；组件停止后不再运行新任务
+func (w *Workers) Go(name string, fn func(ctx context.Context)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() != nil {
+		w.logger.Warn("后台任务组件已停止，忽略新任务", zap.String("worker", name))
+		return
+	}
+	w.running[name]++
+	w.wg.Add(1)
+
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			if w.running[name]--; w.running[name] == 0 {
+				delete(w.running, name)
+			}
+			w.mu.Unlock()
+			w.wg.Done()
+		}()
+		fn(w.ctx)
+	}()
+}
+
+// Running 返回仍在运行的任务名称
+func (w *Workers) Running() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	names := make([]string, 0, len(w.running))
+	for name, n := range w.running {
+		if n > 1 {
+			name = fmt.Sprintf("%s(x%d)", name, n)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start 任务通过 Go 启动，这里无需处理
+func (w *Workers) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop 取消所有任务并等待其退出，ctx 到期时返回仍在运行的任务
+func (w *Workers) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	w.cancel()
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		running := w.Running()
+		w.logger.Warn("关闭超时，后台任务仍在运行", zap.Int("count", len(running)), zap.Strings("workers", running))
+		return fmt.Errorf("%d 个后台任务未退出: %v", len(running), running)
+	}
+}
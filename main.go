@@ -2,31 +2,57 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"go-web-template/buildinfo"
 	"go-web-template/config"
 	"go-web-template/database"
+	"go-web-template/health"
+	"go-web-template/httpclient"
+	"go-web-template/lifecycle"
+	"go-web-template/metrics"
 	"go-web-template/middlewares"
 	"go-web-template/routes"
 	_ "go-web-template/routes/rest" // 导入触发 init() 自动注册路由
+	"go-web-template/tracing"
 	"go-web-template/utils"
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
-	"github.com/openzipkin/zipkin-go"
 	"go.uber.org/zap"
 )
 
+// printConfig 打印生效配置后退出（yaml/json）
+var printConfig = flag.String("print-config", "", "打印脱敏后的生效配置及来源后退出，可选值: yaml, json")
+
 func main() {
-	// 加载配置
+	// 解析命令行参数（-config 指定配置文件路径）
+	flag.Parse()
+
+	// 加载配置（默认值 -> 配置文件 -> APP_ 环境变量）
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatal("加载配置失败:", err)
 	}
 
+	// 打印生效配置（在校验之前输出，便于排查不合法的配置项）
+	if *printConfig != "" {
+		if err := config.WriteEffective(os.Stdout, *printConfig); err != nil {
+			log.Fatal("输出生效配置失败:", err)
+		}
+		return
+	}
+
+	// 校验配置，任何不合法的配置项都拒绝启动
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// 未通过 ldflags 注入版本号时使用 app.version
+	buildinfo.SetAppVersion(cfg.App.Version)
+
 	// 初始化日志系统
 	if err := middlewares.InitLogger(&cfg.Logger); err != nil {
 		log.Fatal("初始化日志系统失败:", err)
@@ -39,11 +65,13 @@ func main() {
 	defer middlewares.Sync()
 
 	// 使用结构化日志记录启动信息
-	zap.L().Info("应用程序启动",
-		zap.String("version", cfg.App.Version),
+	zap.L().Info("应用程序启动", append(buildinfo.Fields(),
 		zap.String("environment", cfg.App.Environment),
 		zap.Bool("debug", cfg.App.Debug),
-	)
+	)...)
+	if cfg.HasPlaceholderJWTSecret() {
+		zap.L().Warn("JWT密钥仍为默认占位值，请在部署前通过 jwt.secret 或 APP_JWT_SECRET 替换")
+	}
 
 	// 初始化时区
 	if err := utils.InitTimezone(cfg.App.Timezone); err != nil {
@@ -52,121 +80,195 @@ func main() {
 		zap.L().Info("时区设置成功", zap.String("timezone", cfg.App.Timezone))
 	}
 
-	// 初始化 Zipkin
-	var zipkinTracer *zipkin.Tracer
-	if cfg.Zipkin.Enabled {
-		tracer, err := utils.InitZipkin(&cfg.Zipkin, zap.L())
-		if err != nil {
-			zap.L().Fatal("Zipkin初始化失败", zap.Error(err))
-		}
-		zipkinTracer = tracer
-		// 设置全局 Zipkin tracer
-		middlewares.SetZipkinTracer(tracer)
-		zap.L().Info("Zipkin初始化成功",
-			zap.String("service_name", cfg.Zipkin.ServiceName),
-			zap.String("endpoint", cfg.Zipkin.Endpoint),
-		)
-
-		// 测试Zipkin连接状态
-		healthChecker := utils.NewZipkinHealthChecker(cfg.Zipkin.Endpoint, zap.L())
-		if healthChecker.CheckConnection() {
-			zap.L().Info("Zipkin服务连接测试成功")
-		} else {
-			zap.L().Warn("Zipkin服务连接测试失败")
-		}
-
-		// 监控tracer状态
-		utils.MonitorZipkinReporter(tracer, zap.L())
-	}
-
-	// 初始化数据库（带 Zipkin tracer 支持）
-	if err := database.InitWithTracer(&cfg.Database, zap.L(), zipkinTracer); err != nil {
-		zap.L().Fatal("数据库初始化失败", zap.Error(err))
+	// 按依赖顺序启动各组件，收到 SIGINT/SIGTERM 后按相反顺序停止
+	app := lifecycle.New(zap.L())
+	registerComponents(app, cfg)
+	if err := app.Run(context.Background(), ms(cfg.Shutdown.Timeout)); err != nil {
+		zap.L().Error("应用程序关闭时出现错误", zap.Error(err))
+		middlewares.Sync()
+		os.Exit(1)
 	}
 
-	// 设置路由
-	r := routes.SetupRoutes(cfg)
+	zap.L().Info("应用程序已优雅关闭")
+}
 
-	// 创建HTTP服务器
-	srv := &http.Server{
-		Addr:    cfg.GetAddr(),
-		Handler: r,
-	}
+// registerComponents 注册应用的各组件及其依赖关系
+func registerComponents(app *lifecycle.Manager, cfg *config.Config) {
+	// 链路追踪，tracing.provider 决定使用 Zipkin 还是 OpenTelemetry；最后停止，上报关闭过程中产生的 span
+	var tracer tracing.Tracer
+	app.Add("tracing", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			switch cfg.TracingProvider() {
+			case "zipkin":
+				sampler := utils.NewZipkinSampler(&cfg.Zipkin, zap.L())
+				zipkinTracing, err := utils.InitZipkin(&cfg.Zipkin, sampler, zap.L())
+				if err != nil {
+					return fmt.Errorf("Zipkin初始化失败: %w", err)
+				}
+				tracer = tracing.NewZipkin(zipkinTracing)
+				zap.L().Info("Zipkin初始化成功",
+					zap.String("service_name", cfg.Zipkin.ServiceName),
+					zap.String("endpoint", cfg.Zipkin.Endpoint),
+				)
 
-	// 启动服务器
-	go func() {
-		zap.L().Info("HTTP 服务器启动",
-			zap.String("address", cfg.GetAddr()),
-			zap.String("version", cfg.App.Version),
-			zap.String("environment", cfg.App.Environment),
-			zap.Bool("debug", cfg.App.Debug),
-		)
+				// 测试Zipkin连接状态
+				healthChecker := utils.NewZipkinHealthChecker(cfg.Zipkin.Endpoint, zap.L())
+				if healthChecker.CheckConnection() {
+					zap.L().Info("Zipkin服务连接测试成功")
+				} else {
+					zap.L().Warn("Zipkin服务连接测试失败")
+				}
 
-		fmt.Printf("服务器启动在: %s (版本: %s, 环境: %s)\n",
-			cfg.GetAddr(), cfg.App.Version, cfg.App.Environment)
+				// 定期记录 span 上报与丢弃数
+				utils.MonitorZipkinReporter(zipkinTracing.Reporter, zap.L())
+			case "otel":
+				otelTracer, err := tracing.NewOTel(ctx, &cfg.Tracing.OTel)
+				if err != nil {
+					return fmt.Errorf("OpenTelemetry初始化失败: %w", err)
+				}
+				tracer = otelTracer
+				zap.L().Info("OpenTelemetry初始化成功",
+					zap.String("service_name", cfg.Tracing.OTel.ServiceName),
+					zap.String("exporter", cfg.Tracing.OTel.Exporter),
+					zap.String("endpoint", cfg.Tracing.OTel.Endpoint),
+					zap.Float64("sample_rate", cfg.Tracing.OTel.SampleRate),
+				)
+			}
+			// 设置全局 tracer，HTTP 中间件与出站HTTP客户端使用
+			tracing.SetTracer(tracer)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if tracer == nil {
+				return nil
+			}
+			// 上报缓冲中的 span
+			return tracer.Shutdown(ctx)
+		},
+	}, lifecycle.StopTimeout(ms(cfg.Shutdown.Tracing)))
 
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			zap.L().Fatal("启动服务器失败", zap.Error(err))
-		}
-	}()
+	// 数据库（带链路追踪支持）
+	app.Add("database", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			return database.InitWithTracer(&cfg.Database, zap.L(), tracer)
+		},
+		OnStop: func(ctx context.Context) error {
+			return database.Close()
+		},
+	}, lifecycle.DependsOn("tracing"), lifecycle.StopTimeout(ms(cfg.Shutdown.Database)))
 
-	// 注册优雅关闭
-	setupGracefulShutdown(srv, zipkinTracer)
+	// 上游服务的HTTP客户端
+	app.Add("httpclient", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			return httpclient.Init(cfg.Upstreams)
+		},
+	}, lifecycle.DependsOn("tracing"))
 
-	// 等待关闭信号
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// 健康检查项
+	app.Add("health", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			health.Init(&cfg.Health, cfg.Consul.ServiceName, buildinfo.GetVersion(), zap.L())
+			health.AddCheck(health.ProbeReadiness, health.DatabaseCheck())
+			if cfg.TracingProvider() == "zipkin" {
+				health.AddCheck(health.ProbeReadiness, health.ZipkinCheck(cfg.Zipkin.Endpoint, zap.L()))
+			}
+			return nil
+		},
+	}, lifecycle.DependsOn("database"))
 
-	zap.L().Info("正在关闭服务器...")
-	gracefulShutdown(srv, zipkinTracer)
-}
+	// 后台任务，在HTTP服务器之后、数据库之前停止
+	workers := lifecycle.NewWorkers(zap.L())
+	app.Add("workers", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			// 配置热加载（监听配置文件变化与 SIGHUP）
+			if cfg.Reload.Enabled {
+				workers.Go("config-watch", func(ctx context.Context) {
+					config.Watch(ctx, time.Duration(cfg.Reload.Interval)*time.Second)
+				})
+				zap.L().Info("配置热加载已启用", zap.Int("interval_seconds", cfg.Reload.Interval))
+			}
 
-// setupGracefulShutdown 设置优雅关闭
-func setupGracefulShutdown(srv *http.Server, tracer *zipkin.Tracer) {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-c
-		zap.L().Info("接收到关闭信号，开始优雅关闭...")
-		gracefulShutdown(srv, tracer)
-		os.Exit(0)
-	}()
-}
+			// SIGUSR1/SIGUSR2 逐级调整日志级别
+			workers.Go("log-level-signals", middlewares.WatchLevelSignals)
+			return workers.Start(ctx)
+		},
+		OnStop: workers.Stop,
+	}, lifecycle.DependsOn("database", "httpclient"), lifecycle.StopTimeout(ms(cfg.Shutdown.Workers)))
 
-// gracefulShutdown 执行优雅关闭
-func gracefulShutdown(srv *http.Server, tracer *zipkin.Tracer) {
-	// 设置关闭超时
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	// 1. 关闭HTTP服务器
-	zap.L().Info("正在关闭HTTP服务器...")
-	if err := srv.Shutdown(ctx); err != nil {
-		zap.L().Error("HTTP服务器关闭失败", zap.Error(err))
-		// 强制关闭
-		if err := srv.Close(); err != nil {
-			zap.L().Error("强制关闭HTTP服务器失败", zap.Error(err))
-		}
-	} else {
-		zap.L().Info("HTTP服务器关闭成功")
+	// 在单独的管理端口暴露 Prometheus 指标，在HTTP服务器之后停止，关闭过程中仍可采集指标
+	httpDeps := []string{"workers", "health"}
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, metrics.Handler())
+		app.Add("metrics-server", &lifecycle.HTTPServer{
+			Name:   "指标服务器",
+			Server: &http.Server{Addr: cfg.Metrics.Addr, Handler: mux},
+			Logger: zap.L(),
+		}, lifecycle.StopTimeout(ms(cfg.Shutdown.Metrics)))
+		httpDeps = append(httpDeps, "metrics-server")
 	}
 
-	// 2. 关闭数据库连接
-	if err := database.Close(); err != nil {
-		zap.L().Error("关闭数据库连接失败", zap.Error(err))
-	} else {
-		zap.L().Info("数据库连接已关闭")
+	// HTTP服务器，关闭超时到期前取消仍在处理的请求的上下文，到期时输出未完成的请求
+	app.Add("http", &lifecycle.HTTPServer{
+		Name:        "HTTP服务器",
+		Server:      &http.Server{Addr: cfg.GetAddr(), Handler: routes.SetupRoutes(cfg)},
+		Logger:      zap.L(),
+		CancelGrace: ms(cfg.Shutdown.CancelGrace),
+		OnCancel: func() {
+			logInFlightRequests("关闭超时即将到期，取消仍在处理的请求")
+		},
+		OnDeadline: func() {
+			logInFlightRequests("关闭超时，强制关闭仍在处理的请求")
+		},
+	}, lifecycle.DependsOn(httpDeps...), lifecycle.StopTimeout(ms(cfg.Shutdown.HTTP)))
+
+	// 全部启动后 startup 探针开始返回 200；最先停止，readiness 探针立即返回 503，
+	// 并在 pre_stop_delay 内继续接收请求，等待负载均衡摘除流量后再关闭HTTP服务器
+	preStopDelay := ms(cfg.Shutdown.PreStopDelay)
+	app.Add("ready", lifecycle.Hooks{
+		OnStart: func(ctx context.Context) error {
+			health.MarkStarted()
+			fmt.Printf("服务器启动在: %s (版本: %s, 环境: %s)\n",
+				cfg.GetAddr(), buildinfo.GetVersion(), cfg.App.Environment)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			health.MarkShuttingDown()
+			if preStopDelay <= 0 {
+				return nil
+			}
+			zap.L().Info("就绪探针已返回 503，等待负载均衡摘除流量", zap.Duration("pre_stop_delay", preStopDelay))
+			timer := time.NewTimer(preStopDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	}, lifecycle.DependsOn("http"), lifecycle.StopTimeout(preStopDelay))
+}
+
+// logInFlightRequests 输出仍在处理的HTTP请求，最多列出 maxLoggedRequests 个
+func logInFlightRequests(msg string) {
+	requests := middlewares.InFlightRequests()
+	if len(requests) == 0 {
+		return
 	}
 
-	// 关闭 Zipkin tracer
-	if tracer != nil {
-		zap.L().Info("Zipkin tracer资源清理完成")
+	now := time.Now()
+	summary := make([]string, 0, min(len(requests), maxLoggedRequests))
+	for _, req := range requests[:min(len(requests), maxLoggedRequests)] {
+		summary = append(summary, fmt.Sprintf("%s %s (request_id=%s, elapsed=%s)",
+			req.Method, req.Path, req.RequestID, now.Sub(req.Start).Round(time.Millisecond)))
 	}
+	zap.L().Warn(msg, zap.Int("count", len(requests)), zap.Strings("requests", summary))
+}
 
-	// 3. 同步日志缓冲区
-	middlewares.Sync()
+// maxLoggedRequests 关闭超时时最多列出的请求数
+const maxLoggedRequests = 20
 
-	zap.L().Info("应用程序已优雅关闭")
+// ms 将毫秒数转换为 time.Duration
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
 }
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry 应用的 Prometheus 指标注册表，各模块的指标都注册到这里
+//
+// 使用独立的注册表而不是 prometheus.DefaultRegisterer，避免第三方库注册的指标混入。
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	// Go 运行时（goroutine、GC、内存）与进程（CPU、文件描述符、RSS）指标
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler 返回暴露指标的HTTP处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// StatusClass 返回状态码分类作为指标的 status 标签，如 200 -> 2xx，入站与出站HTTP指标共用
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return strconv.Itoa(status)
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
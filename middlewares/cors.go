@@ -2,19 +2,71 @@ package middlewares
 
 import (
 	"net/http"
+	"sync/atomic"
+
+	"go-web-template/config"
 
 	"github.com/gin-gonic/gin"
 )
 
-// CORS 跨域中间件
-func CORS() gin.HandlerFunc {
+// corsPolicy 跨域来源白名单，allowAll 为 true 时允许所有来源
+type corsPolicy struct {
+	allowAll bool
+	origins  map[string]struct{}
+}
+
+// newCORSPolicy 根据配置构建跨域策略
+func newCORSPolicy(cfg *config.CORSConfig) *corsPolicy {
+	policy := &corsPolicy{
+		allowAll: len(cfg.AllowOrigins) == 0,
+		origins:  make(map[string]struct{}, len(cfg.AllowOrigins)),
+	}
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			policy.allowAll = true
+		}
+		policy.origins[origin] = struct{}{}
+	}
+	return policy
+}
+
+// allowed 判断来源是否在白名单中
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+	_, ok := p.origins[origin]
+	return ok
+}
+
+// CORS 跨域中间件，来源白名单支持配置热加载
+func CORS(cfg *config.CORSConfig) gin.HandlerFunc {
+	var policy atomic.Pointer[corsPolicy]
+	policy.Store(newCORSPolicy(cfg))
+
+	config.OnChange(func(prev, next *config.Config) {
+		policy.Store(newCORSPolicy(&next.CORS))
+	})
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
+		if origin != "" && !policy.Load().allowed(origin) {
+			// 不在白名单中的来源不返回CORS头部，由浏览器拦截
+			if c.Request.Method == "OPTIONS" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
 		// 设置CORS头部
 		c.Header("Access-Control-Allow-Origin", origin)
+		c.Header("Vary", "Origin")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, b3, X-B3-TraceId, X-B3-SpanId, X-B3-ParentSpanId, X-B3-Sampled, X-B3-Flags, traceparent, tracestate")
+		c.Header("Access-Control-Expose-Headers", RequestIDHeader+", "+TraceIDHeader)
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlightRequest 正在处理的HTTP请求
+type InFlightRequest struct {
+	RequestID string
+	Method    string
+	Path      string
+	Start     time.Time
+}
+
+var (
+	inFlightSeq atomic.Uint64
+	inFlight    sync.Map // uint64 -> *InFlightRequest
+)
+
+// InFlight 记录正在处理的请求，优雅关闭时用于输出超时仍未完成的请求
+//
+// 需要在 RequestLogger 之后注册，才能记录请求ID。
+func InFlight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := inFlightSeq.Add(1)
+		inFlight.Store(id, &InFlightRequest{
+			RequestID: GetRequestID(c),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Start:     time.Now(),
+		})
+		defer inFlight.Delete(id)
+
+		c.Next()
+	}
+}
+
+// InFlightRequests 返回正在处理的请求，按开始时间排序
+func InFlightRequests() []InFlightRequest {
+	var requests []InFlightRequest
+	inFlight.Range(func(_, value interface{}) bool {
+		requests = append(requests, *value.(*InFlightRequest))
+		return true
+	})
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Start.Before(requests[j].Start)
+	})
+	return requests
+}
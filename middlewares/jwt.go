@@ -70,7 +70,7 @@ func JWTAuth() gin.HandlerFunc {
 		// 从Header获取token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			Logger.Warn("JWT认证失败：缺少Authorization头部",
+			LoggerFrom(c.Request.Context()).Warn("JWT认证失败：缺少Authorization头部",
 				zap.String("path", c.Request.URL.Path),
 				zap.String("client_ip", c.ClientIP()),
 			)
@@ -84,8 +84,8 @@ func JWTAuth() gin.HandlerFunc {
 
 		// Bearer token格式检查
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			Logger.Warn("JWT认证失败：token格式错误",
-				zap.String("auth_header", authHeader),
+			LoggerFrom(c.Request.Context()).Warn("JWT认证失败：token格式错误",
+				zap.String("auth_scheme", authScheme(authHeader)),
 				zap.String("path", c.Request.URL.Path),
 				zap.String("client_ip", c.ClientIP()),
 			)
@@ -102,7 +102,7 @@ func JWTAuth() gin.HandlerFunc {
 		// 解析token
 		claims, err := ParseToken(tokenString)
 		if err != nil {
-			Logger.Warn("JWT认证失败：token解析错误",
+			LoggerFrom(c.Request.Context()).Warn("JWT认证失败：token解析错误",
 				zap.Error(err),
 				zap.String("path", c.Request.URL.Path),
 				zap.String("client_ip", c.ClientIP()),
@@ -119,8 +119,10 @@ func JWTAuth() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 
-		Logger.Debug("JWT认证成功",
-			zap.Int("user_id", claims.UserID),
+		// 请求logger携带用户信息
+		AddLoggerFields(c, zap.Int("user_id", claims.UserID))
+
+		LoggerFrom(c.Request.Context()).Debug("JWT认证成功",
 			zap.String("username", claims.Username),
 			zap.String("path", c.Request.URL.Path),
 		)
@@ -129,6 +131,15 @@ func JWTAuth() gin.HandlerFunc {
 	}
 }
 
+// authScheme 返回 Authorization 头部的认证方案（如 Basic），不记录凭据本身
+func authScheme(authHeader string) string {
+	scheme, _, found := strings.Cut(authHeader, " ")
+	if !found {
+		return "(无)"
+	}
+	return scheme
+}
+
 // GetCurrentUser 从上下文获取当前用户信息
 func GetCurrentUser(c *gin.Context) (int, string, bool) {
 	userID, userExists := c.Get("user_id")
@@ -1,88 +1,251 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
 	"os"
+	"runtime/debug"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"go-web-template/config"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var Logger *zap.Logger
 
+// loggerLevel 全局日志级别，支持在运行时调整
+var loggerLevel = zap.NewAtomicLevel()
+
 // InitLogger 初始化日志系统
 func InitLogger(cfg *config.LoggerConfig) error {
-	// 配置编码器
-	var encoderConfig zapcore.EncoderConfig
-	if cfg.Format == "json" {
-		encoderConfig = zap.NewProductionEncoderConfig()
-	} else {
-		encoderConfig = zap.NewDevelopmentEncoderConfig()
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	// 配置日志级别
+	loggerLevel.SetLevel(parseLevel(cfg.Level))
+
+	// 配置脱敏规则
+	policy, err := newRedactPolicy(&cfg.Redact)
+	if err != nil {
+		return err
 	}
-	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-
-	// 选择编码器
-	var encoder zapcore.Encoder
-	if cfg.Format == "json" {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	logRedact.Store(policy)
+
+	// 配置输出，多个输出同时写入，每个输出在写入前脱敏
+	sinks := cfg.LogSinks()
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for i, sink := range sinks {
+		core, err := newSinkCore(sink, cfg.Format)
+		if err != nil {
+			return fmt.Errorf("初始化日志输出 sinks[%d] 失败: %w", i, err)
+		}
+		cores = append(cores, &redactCore{Core: core})
 	}
+	core := zapcore.NewTee(cores...)
 
-	// 配置日志级别
-	level := zap.InfoLevel
-	switch strings.ToLower(cfg.Level) {
+	// 创建logger，全局级别与模块级别由 levelFilterCore 过滤
+	Logger = zap.New(&levelFilterCore{Core: core, enabled: loggerLevel},
+		zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+
+	// 设置为全局logger，这样就可以使用 zap.L() 访问
+	zap.ReplaceGlobals(Logger)
+
+	// 配置热加载时同步调整脱敏规则和日志级别
+	config.OnChange(func(prev, next *config.Config) {
+		if policy, err := newRedactPolicy(&next.Logger.Redact); err == nil {
+			logRedact.Store(policy)
+		}
+		if prev.Logger.Level == next.Logger.Level {
+			return
+		}
+		loggerLevel.SetLevel(parseLevel(next.Logger.Level))
+		Logger.Info("日志级别已更新",
+			zap.String("from", prev.Logger.Level),
+			zap.String("to", next.Logger.Level),
+		)
+	})
+
+	return nil
+}
+
+// parseLevel 解析日志级别，无法识别时使用 info
+func parseLevel(level string) zapcore.Level {
+	switch strings.ToLower(level) {
 	case "debug":
-		level = zap.DebugLevel
-	case "info":
-		level = zap.InfoLevel
+		return zap.DebugLevel
 	case "warn":
-		level = zap.WarnLevel
+		return zap.WarnLevel
 	case "error":
-		level = zap.ErrorLevel
+		return zap.ErrorLevel
+	default:
+		return zap.InfoLevel
 	}
+}
 
-	// 配置输出
-	var writeSyncer zapcore.WriteSyncer
-	if cfg.Output == "file" {
-		lumberjackLogger := &lumberjack.Logger{
-			Filename:   cfg.Filename,
-			MaxSize:    cfg.MaxSize,
-			MaxAge:     cfg.MaxAge,
-			MaxBackups: cfg.MaxBackups,
-			Compress:   cfg.Compress,
-		}
-		writeSyncer = zapcore.AddSync(lumberjackLogger)
-	} else {
-		writeSyncer = zapcore.AddSync(os.Stdout)
+// accessLogPolicy 访问日志的跳过路径与采样率
+type accessLogPolicy struct {
+	skipPaths map[string]struct{}
+	sampling  config.AccessLogSampling
+}
+
+// newAccessLogPolicy 根据配置构建访问日志策略
+func newAccessLogPolicy(cfg *config.AccessLogConfig) *accessLogPolicy {
+	policy := &accessLogPolicy{
+		skipPaths: make(map[string]struct{}, len(cfg.SkipPaths)),
+		sampling:  cfg.Sampling,
+	}
+	for _, path := range cfg.SkipPaths {
+		policy.skipPaths[path] = struct{}{}
 	}
+	return policy
+}
 
-	// 创建core
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+// sampled 按响应状态分类决定是否记录
+func (p *accessLogPolicy) sampled(status int) bool {
+	var rate float64
+	switch {
+	case status >= 500:
+		rate = p.sampling.ServerError
+	case status >= 400:
+		rate = p.sampling.ClientError
+	case status >= 300:
+		rate = p.sampling.Redirect
+	default:
+		rate = p.sampling.Success
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
 
-	// 创建logger
-	Logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+// GinLogger 返回结构化的HTTP访问日志中间件
+//
+// 访问日志通过 zap 输出，与应用日志共用输出目标（stdout 或 lumberjack 滚动文件）和格式。
+// 跳过路径与采样率支持配置热加载。
+func GinLogger(cfg *config.AccessLogConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
 
-	// 设置为全局logger，这样就可以使用 zap.L() 访问
-	zap.ReplaceGlobals(Logger)
+	var policy atomic.Pointer[accessLogPolicy]
+	policy.Store(newAccessLogPolicy(cfg))
 
-	return nil
-}
+	config.OnChange(func(prev, next *config.Config) {
+		policy.Store(newAccessLogPolicy(&next.Logger.AccessLog))
+	})
 
-// GinLogger 返回gin的日志中间件
-func GinLogger() gin.HandlerFunc {
-	return gin.LoggerWithWriter(os.Stdout)
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		p := policy.Load()
+		if _, skip := p.skipPaths[path]; skip {
+			return
+		}
+		status := c.Writer.Status()
+		if !p.sampled(status) {
+			return
+		}
+
+		bytesIn := c.Request.ContentLength
+		if bytesIn < 0 {
+			bytesIn = 0
+		}
+		bytesOut := c.Writer.Size()
+		if bytesOut < 0 {
+			bytesOut = 0
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("path", path),
+			zap.String("query", c.Request.URL.RawQuery),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int64("bytes_in", bytesIn),
+			zap.Int("bytes_out", bytesOut),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		// 访问日志不需要调用堆栈，5xx 的错误详情由 GinRecovery 或业务日志记录
+		logger := LoggerFrom(c.Request.Context()).Named("access").WithOptions(zap.AddStacktrace(zapcore.FatalLevel))
+		switch {
+		case status >= 500:
+			logger.Error("HTTP请求", fields...)
+		case status >= 400:
+			logger.Warn("HTTP请求", fields...)
+		default:
+			logger.Info("HTTP请求", fields...)
+		}
+	}
 }
 
-// GinRecovery 返回gin的恢复中间件
+// GinRecovery 返回异常恢复中间件，panic 通过 zap 记录为带堆栈的结构化日志
 func GinRecovery() gin.HandlerFunc {
-	return gin.RecoveryWithWriter(os.Stdout)
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			logger := LoggerFrom(c.Request.Context()).WithOptions(zap.AddStacktrace(zapcore.FatalLevel))
+			fields := []zap.Field{
+				zap.Any("panic", r),
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("client_ip", c.ClientIP()),
+				zap.String("stack", string(debug.Stack())),
+			}
+
+			// 客户端断开连接时无法再写入响应，无需按服务端错误处理
+			if isBrokenPipe(r) {
+				logger.Warn("客户端连接已断开", fields...)
+				if err, ok := r.(error); ok {
+					_ = c.Error(err)
+				}
+				c.Abort()
+				return
+			}
+
+			logger.Error("请求处理发生panic", fields...)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "服务器内部错误",
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+// isBrokenPipe 判断panic是否由客户端断开连接引起
+func isBrokenPipe(r interface{}) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if !errors.As(opErr, &sysErr) {
+		return false
+	}
+	msg := strings.ToLower(sysErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
 }
 
 // ErrorLogging 错误响应日志中间件
@@ -92,7 +255,7 @@ func ErrorLogging() gin.HandlerFunc {
 
 		// 记录错误状态码的响应
 		if c.Writer.Status() >= 400 {
-			Logger.Warn("HTTP错误响应",
+			LoggerFrom(c.Request.Context()).Warn("HTTP错误响应",
 				zap.String("method", c.Request.Method),
 				zap.String("path", c.Request.URL.Path),
 				zap.String("client_ip", c.ClientIP()),
@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志级别在运行时可调整：
+//   - 全局级别 loggerLevel 作用于 Logger 及其派生的请求logger
+//   - 模块级别通过 ForModule 生效，例如 GORM SQL日志使用 gorm 模块，
+//     可单独调到 debug 而其他日志保持 info；模块未设置级别时跟随全局级别
+
+// ModuleGorm GORM SQL日志所属模块
+const ModuleGorm = "gorm"
+
+// 可按信号逐级调整的日志级别，从最详细到最简略
+var levelSteps = []zapcore.Level{zap.DebugLevel, zap.InfoLevel, zap.WarnLevel, zap.ErrorLevel}
+
+var (
+	moduleLevelsMu sync.RWMutex
+	moduleLevels   = make(map[string]zap.AtomicLevel)
+)
+
+// levelFilterCore 按动态级别过滤日志的core
+//
+// 底层core不做级别过滤，由 enabled 决定是否输出，
+// 因此同一个底层core可以同时服务于不同级别的模块logger。
+type levelFilterCore struct {
+	zapcore.Core
+	enabled zapcore.LevelEnabler
+}
+
+// Enabled 实现 zapcore.Core
+func (c *levelFilterCore) Enabled(level zapcore.Level) bool {
+	return c.enabled.Enabled(level)
+}
+
+// With 实现 zapcore.Core
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), enabled: c.enabled}
+}
+
+// Check 实现 zapcore.Core
+func (c *levelFilterCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabled.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+// moduleEnabler 模块级别判断，未设置模块级别时使用全局级别
+type moduleEnabler string
+
+// Enabled 实现 zapcore.LevelEnabler
+func (m moduleEnabler) Enabled(level zapcore.Level) bool {
+	moduleLevelsMu.RLock()
+	moduleLevel, ok := moduleLevels[string(m)]
+	moduleLevelsMu.RUnlock()
+	if ok {
+		return moduleLevel.Enabled(level)
+	}
+	return loggerLevel.Enabled(level)
+}
+
+// ForModule 返回按模块级别过滤的logger，保留原logger已携带的字段
+func ForModule(logger *zap.Logger, module string) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if filtered, ok := core.(*levelFilterCore); ok {
+			core = filtered.Core
+		}
+		return &levelFilterCore{Core: core, enabled: moduleEnabler(module)}
+	}))
+}
+
+// GetLevel 获取全局日志级别
+func GetLevel() zapcore.Level {
+	return loggerLevel.Level()
+}
+
+// SetLevel 设置全局日志级别
+func SetLevel(level zapcore.Level) {
+	loggerLevel.SetLevel(level)
+}
+
+// SetModuleLevel 设置模块日志级别
+func SetModuleLevel(module string, level zapcore.Level) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	if moduleLevel, ok := moduleLevels[module]; ok {
+		moduleLevel.SetLevel(level)
+		return
+	}
+	moduleLevels[module] = zap.NewAtomicLevelAt(level)
+}
+
+// ResetModuleLevel 清除模块日志级别，恢复跟随全局级别
+func ResetModuleLevel(module string) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	delete(moduleLevels, module)
+}
+
+// GetModuleLevels 获取所有单独设置了级别的模块
+func GetModuleLevels() map[string]string {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	levels := make(map[string]string, len(moduleLevels))
+	for module, level := range moduleLevels {
+		levels[module] = level.String()
+	}
+	return levels
+}
+
+// StepLevel 将全局日志级别调整一档，verbose 为 true 时更详细（如 info -> debug），
+// 返回调整后的级别
+func StepLevel(verbose bool) zapcore.Level {
+	current := loggerLevel.Level()
+	i := sort.Search(len(levelSteps), func(i int) bool { return levelSteps[i] >= current })
+	switch {
+	case verbose && i > 0:
+		i--
+	case !verbose && i < len(levelSteps)-1:
+		i++
+	}
+	if i >= len(levelSteps) {
+		i = len(levelSteps) - 1
+	}
+	loggerLevel.SetLevel(levelSteps[i])
+	return levelSteps[i]
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package middlewares
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// WatchLevelSignals 监听 SIGUSR1/SIGUSR2 逐级调整全局日志级别，阻塞直到 ctx 取消
+//
+// SIGUSR1 使日志更详细（如 info -> debug），SIGUSR2 使日志更简略（如 info -> warn）。
+func WatchLevelSignals(ctx context.Context) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-sig:
+			from := GetLevel()
+			to := StepLevel(s == syscall.SIGUSR1)
+			Logger.Warn("接收到信号，日志级别已调整",
+				zap.String("signal", s.String()),
+				zap.String("from", from.String()),
+				zap.String("to", to.String()),
+			)
+		}
+	}
+}
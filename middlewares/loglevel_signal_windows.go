@@ -0,0 +1,10 @@
+//go:build windows
+
+package middlewares
+
+import "context"
+
+// WatchLevelSignals Windows 不支持 SIGUSR1/SIGUSR2，请使用管理接口调整日志级别；阻塞直到 ctx 取消
+func WatchLevelSignals(ctx context.Context) {
+	<-ctx.Done()
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"fmt"
+	"os"
+
+	"go-web-template/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// newSinkCore 根据日志输出配置创建core，defaultFormat 为未单独指定格式时使用的 logger.format
+//
+// 各输出的级别阈值在此处生效，全局级别与模块级别由外层的 levelFilterCore 控制。
+func newSinkCore(sink config.LogSinkConfig, defaultFormat string) (zapcore.Core, error) {
+	format := sink.Format
+	if format == "" {
+		format = defaultFormat
+	}
+
+	// 只有终端输出使用彩色级别，避免文件和 syslog 中出现控制字符
+	colored := sink.Type == "stdout" || sink.Type == "stderr" || sink.Type == ""
+	level := zapcore.DebugLevel
+	if sink.Level != "" {
+		level = parseLevel(sink.Level)
+	}
+	encoder := newEncoder(format, colored)
+
+	var writeSyncer zapcore.WriteSyncer
+	switch sink.Type {
+	case "stdout", "":
+		writeSyncer = zapcore.Lock(os.Stdout)
+	case "stderr":
+		writeSyncer = zapcore.Lock(os.Stderr)
+	case "file":
+		writeSyncer = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   sink.Filename,
+			MaxSize:    sink.MaxSize,
+			MaxAge:     sink.MaxAge,
+			MaxBackups: sink.MaxBackups,
+			Compress:   sink.Compress,
+		})
+	case "syslog":
+		core, err := newSyslogCore(sink.Address, sink.Tag, encoder, level)
+		if err != nil {
+			return nil, fmt.Errorf("连接 syslog 失败: %w", err)
+		}
+		return core, nil
+	default:
+		return nil, fmt.Errorf("不支持的日志输出类型 %q", sink.Type)
+	}
+
+	return zapcore.NewCore(encoder, writeSyncer, level), nil
+}
+
+// newEncoder 创建 console 或 json 编码器
+func newEncoder(format string, colored bool) zapcore.Encoder {
+	var encoderConfig zapcore.EncoderConfig
+	if format == "json" {
+		encoderConfig = zap.NewProductionEncoderConfig()
+	} else {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		if colored {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+	}
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	if format == "json" {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
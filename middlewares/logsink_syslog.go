@@ -0,0 +1,78 @@
+//go:build !windows && !plan9
+
+package middlewares
+
+import (
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogCore 连接 syslog 并创建core，address 为空时连接本地 /dev/log 等套接字
+//
+// systemd 环境下 /dev/log 由 journald 接管，因此同样适用于 journald。
+// 每条日志按级别映射为对应的 syslog 严重级别，journald/rsyslog 可按严重级别过滤和路由。
+func newSyslogCore(address, tag string, enc zapcore.Encoder, level zapcore.LevelEnabler) (zapcore.Core, error) {
+	network := ""
+	if address != "" {
+		network = "unixgram"
+	}
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogCore{LevelEnabler: level, enc: enc, w: w}, nil
+}
+
+// syslogCore 按日志级别写入对应严重级别的 syslog core
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslog.Writer
+}
+
+// With 返回附加了字段的core
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &syslogCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), w: c.w}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check 级别满足时加入待写入的core
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 编码日志并以对应的严重级别写入 syslog
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := buf.String()
+	buf.Free()
+
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return c.w.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.w.Info(msg)
+	case zapcore.WarnLevel:
+		return c.w.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.w.Err(msg)
+	default:
+		// DPanic、Panic、Fatal
+		return c.w.Crit(msg)
+	}
+}
+
+// Sync syslog 无缓冲，无需同步
+func (c *syslogCore) Sync() error {
+	return nil
+}
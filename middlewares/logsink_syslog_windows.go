@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package middlewares
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogCore 当前平台不支持 syslog
+func newSyslogCore(address, tag string, enc zapcore.Encoder, level zapcore.LevelEnabler) (zapcore.Core, error) {
+	return nil, errors.New("当前平台不支持 syslog")
+}
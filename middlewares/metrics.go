@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"sync"
+	"time"
+
+	"go-web-template/config"
+	"go-web-template/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute 未匹配到路由的请求使用的 route 标签，避免按原始路径产生大量时间序列
+const unmatchedRoute = "unmatched"
+
+// HTTP请求指标，按请求方法、路由模板和状态码分类打标签
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP请求总数",
+	}, []string{"method", "route", "status"})
+	httpInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "正在处理的HTTP请求数",
+	}, []string{"method", "route"})
+
+	// httpDuration 分桶由 metrics.buckets 配置，首次调用 Metrics 时创建并注册
+	httpDuration     *prometheus.HistogramVec
+	httpDurationOnce sync.Once
+)
+
+func init() {
+	metrics.Registry.MustRegister(httpRequests, httpInFlight)
+}
+
+// Metrics 返回记录HTTP请求数、耗时与处理中请求数的 Prometheus 指标中间件
+//
+// 指标按请求方法、路由模板（c.FullPath()）和状态码分类（2xx、4xx 等）打标签。
+// 可多次调用（如创建多个 gin.Engine），耗时直方图的分桶以第一次调用时的配置为准。
+func Metrics(cfg *config.MetricsConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	httpDurationOnce.Do(func() {
+		httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP请求处理耗时（秒）",
+			Buckets: cfg.Buckets,
+		}, []string{"method", "route", "status"})
+		metrics.Registry.MustRegister(httpDuration)
+	})
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		method := c.Request.Method
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		gauge := httpInFlight.WithLabelValues(method, route)
+		gauge.Inc()
+		defer gauge.Dec()
+
+		c.Next()
+
+		status := metrics.StatusClass(c.Writer.Status())
+		httpRequests.WithLabelValues(method, route, status).Inc()
+		httpDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
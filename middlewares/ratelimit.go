@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"go-web-template/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit 全局限流中间件（令牌桶），开关、速率和突发容量支持配置热加载
+//
+// skipPaths 中的请求路径不限流，用于健康检查探针和指标采集：
+// 探针被限流时 kubelet 会重启正常的 Pod 或将其摘除流量。
+func RateLimit(cfg *config.RateLimitConfig, skipPaths ...string) gin.HandlerFunc {
+	var enabled atomic.Bool
+	enabled.Store(cfg.Enabled)
+	limiter := rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), cfg.Burst)
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	config.OnChange(func(prev, next *config.Config) {
+		if prev.RateLimit == next.RateLimit {
+			return
+		}
+		limiter.SetLimit(rate.Limit(next.RateLimit.RequestsPerSecond))
+		limiter.SetBurst(next.RateLimit.Burst)
+		enabled.Store(next.RateLimit.Enabled)
+		Logger.Info("限流配置已更新",
+			zap.Bool("enabled", next.RateLimit.Enabled),
+			zap.Float64("requests_per_second", next.RateLimit.RequestsPerSecond),
+			zap.Int("burst", next.RateLimit.Burst),
+		)
+	})
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok || !enabled.Load() || limiter.Allow() {
+			c.Next()
+			return
+		}
+
+		LoggerFrom(c.Request.Context()).Warn("请求被限流",
+			zap.String("path", c.Request.URL.Path),
+			zap.String("client_ip", c.ClientIP()),
+		)
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"code":    429,
+			"message": "请求过于频繁，请稍后再试",
+		})
+		c.Abort()
+	}
+}
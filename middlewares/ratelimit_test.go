@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-web-template/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRateLimitSkipsProbes(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), zap.New(core).With(zap.String("request_id", "req-1"))))
+	})
+	// 令牌桶只有 1 个令牌且不补充
+	r.Use(RateLimit(&config.RateLimitConfig{Enabled: true, RequestsPerSecond: 0, Burst: 1}, "/api/health/live"))
+	r.GET("/api/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/health/live", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	if got := serve("/api/users"); got != http.StatusOK {
+		t.Fatalf("第一个请求 status = %d, want 200", got)
+	}
+	if got := serve("/api/users"); got != http.StatusTooManyRequests {
+		t.Fatalf("令牌耗尽后 status = %d, want 429", got)
+	}
+	// 令牌耗尽时探针仍然返回 200
+	for i := 0; i < 3; i++ {
+		if got := serve("/api/health/live"); got != http.StatusOK {
+			t.Errorf("令牌耗尽后探针 status = %d, want 200", got)
+		}
+	}
+
+	// 限流日志使用请求级logger
+	entries := logs.FilterMessage("请求被限流").All()
+	if len(entries) != 1 {
+		t.Fatalf("限流日志 %d 条, want 1", len(entries))
+	}
+	if got := entries[0].ContextMap()["request_id"]; got != "req-1" {
+		t.Errorf("request_id = %v, want req-1", got)
+	}
+}
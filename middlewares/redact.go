@@ -0,0 +1,208 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go-web-template/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue 脱敏后的占位值，与生效配置中的脱敏值一致
+const redactedValue = "******"
+
+var (
+	// bearerPattern Authorization 头部中的 Bearer 令牌，保留 Bearer 前缀便于排查
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[^\s"',;]+`)
+	// jwtPattern 形如 header.payload.signature 的 JWT
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// redactPolicy 日志脱敏规则，为 nil 时不脱敏
+type redactPolicy struct {
+	fields   []string
+	patterns []*regexp.Regexp
+
+	// keyValue 字符串中键名包含敏感字段的 key=value，如查询参数 password=xxx&access_token=xxx
+	keyValue *regexp.Regexp
+	// jsonValue JSON 中键名包含敏感字段的值，如 "password":"xxx"
+	jsonValue *regexp.Regexp
+}
+
+// logRedact 当前脱敏规则，支持配置热加载
+var logRedact atomic.Pointer[redactPolicy]
+
+// newRedactPolicy 根据配置构建脱敏规则，未开启时返回 nil
+func newRedactPolicy(cfg *config.RedactConfig) (*redactPolicy, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	policy := &redactPolicy{
+		patterns: []*regexp.Regexp{bearerPattern, jwtPattern},
+	}
+	for _, field := range cfg.Fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			policy.fields = append(policy.fields, field)
+		}
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("脱敏正则表达式 %q 不合法: %w", pattern, err)
+		}
+		policy.patterns = append(policy.patterns, re)
+	}
+	if len(policy.fields) > 0 {
+		quoted := make([]string, len(policy.fields))
+		for i, field := range policy.fields {
+			quoted[i] = regexp.QuoteMeta(field)
+		}
+		keys := strings.Join(quoted, "|")
+		policy.keyValue = regexp.MustCompile(`(?i)([\w.-]*(?:` + keys + `)[\w.-]*=)[^\s&,;"']+`)
+		policy.jsonValue = regexp.MustCompile(`(?i)("[^"]*(?:` + keys + `)[^"]*"\s*:\s*)(?:"(?:[^"\\]|\\.)*"|-?[\w.+-]+)`)
+	}
+	return policy, nil
+}
+
+// sensitiveKey 判断字段名是否需要整体脱敏
+func (p *redactPolicy) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range p.fields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString 脱敏字符串中匹配的内容
+func (p *redactPolicy) redactString(s string) string {
+	if p.keyValue != nil {
+		s = p.keyValue.ReplaceAllString(s, "${1}"+redactedValue)
+		s = p.jsonValue.ReplaceAllString(s, `${1}"`+redactedValue+`"`)
+	}
+	for _, re := range p.patterns {
+		if re == bearerPattern {
+			s = re.ReplaceAllString(s, "${1}"+redactedValue)
+			continue
+		}
+		s = re.ReplaceAllString(s, redactedValue)
+	}
+	return s
+}
+
+// redactFields 脱敏字段，敏感字段名整体替换，字符串类字段值按规则替换
+//
+// 数组、对象和任意类型（zap.Strings、zap.Object、zap.Any 等）编码为 JSON 后按规则替换。
+func (p *redactPolicy) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		switch {
+		case f.Type == zapcore.InlineMarshalerType:
+			// 内联对象没有自己的键名，展开后逐个字段处理
+			redacted = append(redacted, p.redactEncoded(f)...)
+		case p.sensitiveKey(f.Key):
+			redacted = append(redacted, zap.String(f.Key, redactedValue))
+		case f.Type == zapcore.StringType:
+			redacted = append(redacted, zap.String(f.Key, p.redactString(f.String)))
+		case f.Type == zapcore.ByteStringType:
+			redacted = append(redacted, zap.String(f.Key, p.redactString(string(f.Interface.([]byte)))))
+		case f.Type == zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok && err != nil {
+				redacted = append(redacted, zap.String(f.Key, p.redactString(err.Error())))
+			} else {
+				redacted = append(redacted, f)
+			}
+		case f.Type == zapcore.StringerType:
+			redacted = append(redacted, zap.String(f.Key, p.redactString(stringerValue(f.Interface))))
+		case f.Type == zapcore.ArrayMarshalerType, f.Type == zapcore.ObjectMarshalerType, f.Type == zapcore.ReflectType:
+			redacted = append(redacted, p.redactEncoded(f)...)
+		default:
+			redacted = append(redacted, f)
+		}
+	}
+	return redacted
+}
+
+// redactEncoded 将复杂类型的字段编码为 JSON 后脱敏，结果仍为合法 JSON 时保持原有结构输出，否则输出为字符串
+func (p *redactPolicy) redactEncoded(f zapcore.Field) []zapcore.Field {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]zapcore.Field, 0, len(keys))
+	for _, key := range keys {
+		if p.sensitiveKey(key) {
+			fields = append(fields, zap.String(key, redactedValue))
+			continue
+		}
+		data, err := json.Marshal(enc.Fields[key])
+		if err != nil {
+			fields = append(fields, zap.String(key, redactedValue))
+			continue
+		}
+		scrubbed := p.redactString(string(data))
+		if json.Valid([]byte(scrubbed)) {
+			fields = append(fields, zap.Reflect(key, json.RawMessage(scrubbed)))
+		} else {
+			fields = append(fields, zap.String(key, scrubbed))
+		}
+	}
+	return fields
+}
+
+// stringerValue 安全地获取 fmt.Stringer 的值
+func stringerValue(v interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("<PANIC=%v>", r)
+		}
+	}()
+	if stringer, ok := v.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// redactCore 在写入前对消息和字段脱敏的core
+//
+// 包裹在每个输出的core外层，Check 时按内层core的级别判断，保证各输出的级别阈值不受影响。
+type redactCore struct {
+	zapcore.Core
+}
+
+// With 实现 zapcore.Core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	if policy := logRedact.Load(); policy != nil {
+		fields = policy.redactFields(fields)
+	}
+	return &redactCore{Core: c.Core.With(fields)}
+}
+
+// Check 实现 zapcore.Core
+func (c *redactCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if policy := logRedact.Load(); policy != nil {
+		entry.Message = policy.redactString(entry.Message)
+		fields = policy.redactFields(fields)
+	}
+	return c.Core.Write(entry, fields)
+}
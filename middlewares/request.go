@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader 请求ID头部，客户端传入时沿用，否则由服务端生成
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 允许沿用的客户端请求ID最大长度
+const maxRequestIDLength = 128
+
+// loggerKey 请求上下文中logger的键
+type loggerKey struct{}
+
+// RequestLogger 请求日志上下文中间件
+//
+// 生成或沿用 X-Request-ID，派生携带 request_id 的子logger并存入请求上下文。
+// 后续的追踪中间件和 JWTAuth 会通过 AddLoggerFields 追加 trace_id、span_id、user_id。
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		logger := LoggerFrom(c.Request.Context()).With(zap.String("request_id", requestID))
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
+
+// WithLogger 返回携带指定logger的上下文
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFrom 获取上下文中的请求logger，没有时返回全局Logger
+//
+// 处理函数中应使用 LoggerFrom(c.Request.Context())，数据库操作需通过
+// database.DB.WithContext(c.Request.Context()) 传递上下文，SQL日志才会带上请求信息。
+func LoggerFrom(ctx context.Context) *zap.Logger {
+	if logger, ok := ContextLogger(ctx); ok {
+		return logger
+	}
+	if Logger != nil {
+		return Logger
+	}
+	return zap.L()
+}
+
+// ContextLogger 获取上下文中的请求logger
+func ContextLogger(ctx context.Context) (*zap.Logger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	logger, ok := ctx.Value(loggerKey{}).(*zap.Logger)
+	return logger, ok
+}
+
+// AddLoggerFields 为当前请求的logger追加字段
+func AddLoggerFields(c *gin.Context, fields ...zap.Field) {
+	logger := LoggerFrom(c.Request.Context()).With(fields...)
+	c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+}
+
+// GetRequestID 获取当前请求的请求ID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
+// newRequestID 生成随机请求ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
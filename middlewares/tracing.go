@@ -3,28 +3,24 @@ package middlewares
 import (
 	"context"
 	"fmt"
+	"net/http"
+
+	"go-web-template/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/openzipkin/zipkin-go"
 	"go.uber.org/zap"
 )
 
-var zipkinTracer *zipkin.Tracer
-
-// SetZipkinTracer 设置全局Zipkin tracer
-func SetZipkinTracer(tracer *zipkin.Tracer) {
-	zipkinTracer = tracer
-}
-
-// GetZipkinTracer 获取全局Zipkin tracer
-func GetZipkinTracer() *zipkin.Tracer {
-	return zipkinTracer
-}
+// TraceIDHeader 响应中返回追踪ID的头部，便于客户端反馈问题时定位链路
+const TraceIDHeader = "X-Trace-Id"
 
 // TracingMiddleware 链路追踪中间件
+//
+// 从请求头提取上游的追踪上下文（B3 单头或多头，其次 W3C traceparent），
+// 创建 server 类型的 span 并在响应头 X-Trace-Id 中返回追踪ID。
 func TracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if zipkinTracer == nil {
+		if !tracing.Enabled() {
 			c.Next()
 			return
 		}
@@ -32,10 +28,26 @@ func TracingMiddleware() gin.HandlerFunc {
 		// 创建span名称
 		spanName := fmt.Sprintf("%s %s", c.Request.Method, c.FullPath())
 
-		// 创建span
-		span := zipkinTracer.StartSpan(spanName)
+		// 创建span，存在上游追踪上下文时作为其子span
+		ctx := tracing.Extract(c.Request.Context(), c.Request)
+		ctx, span := tracing.StartSpan(ctx, spanName,
+			tracing.WithKind(tracing.KindServer),
+			tracing.WithRoute(c.Request.URL.Path),
+		)
 		defer span.Finish()
 
+		// 异常恢复中间件在外层，panic 时不会执行 c.Next() 之后的代码：
+		// 在此按 500 记录错误后继续抛出，由异常恢复中间件写入响应
+		defer func() {
+			if r := recover(); r != nil {
+				span.Tag("http.status_code", fmt.Sprintf("%d", http.StatusInternalServerError))
+				span.SetError(fmt.Sprintf("panic: %v", r))
+				panic(r)
+			}
+		}()
+
+		c.Header(TraceIDHeader, span.Context().TraceID.String())
+
 		// 设置span标签
 		span.Tag("http.method", c.Request.Method)
 		span.Tag("http.url", c.Request.URL.String())
@@ -44,9 +56,14 @@ func TracingMiddleware() gin.HandlerFunc {
 		span.Tag("client_ip", c.ClientIP())
 
 		// 将span上下文传递给后续处理
-		ctx := zipkin.NewContext(c.Request.Context(), span)
 		c.Request = c.Request.WithContext(ctx)
 
+		// 请求logger携带追踪信息
+		AddLoggerFields(c,
+			zap.String("trace_id", span.Context().TraceID.String()),
+			zap.String("span_id", span.Context().SpanID.String()),
+		)
+
 		c.Next()
 
 		// 设置响应状态码
@@ -54,41 +71,33 @@ func TracingMiddleware() gin.HandlerFunc {
 
 		// 如果是错误状态码，记录错误
 		if c.Writer.Status() >= 400 {
-			span.Tag("error", "true")
+			message := http.StatusText(c.Writer.Status())
 			if len(c.Errors) > 0 {
-				span.Tag("error.message", c.Errors.String())
+				message = c.Errors.String()
 			}
+			span.SetError(message)
 		}
 
-		Logger.Debug("链路追踪记录完成",
+		LoggerFrom(c.Request.Context()).Debug("链路追踪记录完成",
 			zap.String("span_name", spanName),
-			zap.String("trace_id", span.Context().TraceID.String()),
 			zap.Int("status_code", c.Writer.Status()),
 		)
 	}
 }
 
-// StartSpan 在指定上下文中开始一个新的span
-func StartSpan(ctx context.Context, operationName string) (context.Context, zipkin.Span) {
-	if zipkinTracer == nil {
-		return ctx, nil
-	}
-
-	span := zipkinTracer.StartSpan(operationName, zipkin.Parent(zipkin.SpanFromContext(ctx).Context()))
-	newCtx := zipkin.NewContext(ctx, span)
-
-	return newCtx, span
+// StartSpan 在指定上下文中开始一个新的span，未启用链路追踪时返回 nil
+func StartSpan(ctx context.Context, operationName string) (context.Context, tracing.Span) {
+	return tracing.StartSpan(ctx, operationName)
 }
 
 // FinishSpan 完成span并记录可选的错误信息
-func FinishSpan(span zipkin.Span, err error) {
+func FinishSpan(span tracing.Span, err error) {
 	if span == nil {
 		return
 	}
 
 	if err != nil {
-		span.Tag("error", "true")
-		span.Tag("error.message", err.Error())
+		span.SetError(err.Error())
 	}
 
 	span.Finish()
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"net/http"
+
+	"go-web-template/config"
+	"go-web-template/middlewares"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Config 返回当前生效配置（敏感字段已脱敏）及每个配置项的来源
+func Config(c *gin.Context) {
+	_, username, _ := middlewares.GetCurrentUser(c)
+	middlewares.LoggerFrom(c.Request.Context()).Info("查看生效配置",
+		zap.String("username", username),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    config.Effective(),
+	})
+}
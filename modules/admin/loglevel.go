@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"net/http"
+
+	"go-web-template/middlewares"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelRequest 调整日志级别请求
+//
+// Module 为空时调整全局级别；Module 不为空且 Level 为空时清除该模块的级别，恢复跟随全局级别。
+type logLevelRequest struct {
+	Level  string `json:"level"`
+	Module string `json:"module"`
+}
+
+// GetLogLevel 返回全局日志级别及单独设置了级别的模块
+func GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    logLevels(),
+	})
+}
+
+// SetLogLevel 在运行时调整全局或模块日志级别
+func SetLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if req.Module != "" && req.Level == "" {
+		middlewares.ResetModuleLevel(req.Module)
+	} else {
+		level, err := zapcore.ParseLevel(req.Level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "日志级别不合法: " + req.Level,
+			})
+			return
+		}
+		if req.Module == "" {
+			middlewares.SetLevel(level)
+		} else {
+			middlewares.SetModuleLevel(req.Module, level)
+		}
+	}
+
+	_, username, _ := middlewares.GetCurrentUser(c)
+	middlewares.LoggerFrom(c.Request.Context()).Warn("日志级别已调整",
+		zap.String("username", username),
+		zap.String("module", req.Module),
+		zap.String("level", req.Level),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    logLevels(),
+	})
+}
+
+// logLevels 当前日志级别
+func logLevels() gin.H {
+	return gin.H{
+		"level":   middlewares.GetLevel().String(),
+		"modules": middlewares.GetModuleLevels(),
+	}
+}
@@ -3,7 +3,11 @@ package example
 import (
 	"net/http"
 
+	"go-web-template/buildinfo"
+	"go-web-template/config"
+	"go-web-template/health"
 	"go-web-template/middlewares"
+	"go-web-template/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -11,41 +15,51 @@ import (
 
 // Ping ping接口 - 简单的存活检查
 func Ping(c *gin.Context) {
-	middlewares.Logger.Debug("健康检查请求")
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":      "pong",
-		"version":      "1.0.0",
-		"environment":  "development",
-		"timezone":     "UTC",
-		"current_time": getCurrentTimeString(),
-	})
+	middlewares.LoggerFrom(c.Request.Context()).Debug("健康检查请求")
+
+	info := buildinfo.Get()
+	response := PingResponse{
+		Message:     "pong",
+		Version:     info.Version,
+		GitCommit:   info.GitCommit,
+		Timezone:    utils.GetTimezone(),
+		CurrentTime: utils.GetCurrentTimeString(),
+		Uptime:      info.Uptime,
+	}
+	if cfg := config.Current(); cfg != nil {
+		response.Environment = cfg.App.Environment
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Version version接口 - 构建与运行信息
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
 }
 
 // Health health接口 - 完整的健康检查
+//
+// 执行所有 readiness 检查项（数据库、Zipkin 及模块注册的检查），不健康时返回 503。
 func Health(c *gin.Context) {
-	middlewares.Logger.Debug("完整健康检查请求",
+	logger := middlewares.LoggerFrom(c.Request.Context())
+	logger.Debug("完整健康检查请求",
 		zap.String("client_ip", c.ClientIP()),
 		zap.String("user_agent", c.GetHeader("User-Agent")),
 	)
 
-	// 返回简单的健康状态
-	response := HealthResponse{
-		Status:    HealthStatusHealthy,
-		Version:   "1.0.0",
-		Timestamp: getCurrentTime(),
-		Checks: []HealthCheck{
-			{
-				Name:    "database",
-				Status:  HealthStatusHealthy,
-				Message: "数据库连接正常",
-			},
-		},
+	response := health.Run(c.Request.Context(), health.ProbeReadiness)
+	info := buildinfo.Get()
+	response.Build = &info
+
+	code := http.StatusOK
+	if response.Status == health.HealthStatusUnhealthy {
+		code = http.StatusServiceUnavailable
 	}
 
-	middlewares.Logger.Debug("健康检查通过",
+	logger.Debug("健康检查完成",
 		zap.String("status", string(response.Status)),
 	)
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(code, response)
 }
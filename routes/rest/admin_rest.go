@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"go-web-template/modules/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	// 注册管理路由（需要JWT认证）
+	RegisterPrivate(registerAdminPrivateRoutes)
+}
+
+// registerAdminPrivateRoutes 注册管理私有路由
+func registerAdminPrivateRoutes(r *gin.RouterGroup) {
+	adminGroup := r.Group("/admin")
+	adminGroup.GET("/config", admin.Config)         // 生效配置（脱敏）
+	adminGroup.GET("/log-level", admin.GetLogLevel) // 当前日志级别
+	adminGroup.PUT("/log-level", admin.SetLogLevel) // 调整日志级别
+}
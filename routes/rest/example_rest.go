@@ -17,8 +17,9 @@ func init() {
 // registerExamplePublicRoutes 注册示例公开路由
 func registerExamplePublicRoutes(r *gin.RouterGroup) {
 	// 健康检查相关路由
-	r.GET("/ping", example.Ping)     // ping接口 - 简单的存活检查
-	r.GET("/health", example.Health) // health接口 - 完整的健康检查
+	r.GET("/ping", example.Ping)       // ping接口 - 简单的存活检查
+	r.GET("/health", example.Health)   // health接口 - 完整的健康检查
+	r.GET("/version", example.Version) // version接口 - 构建与运行信息
 }
 
 // registerExamplePrivateRoutes 注册示例私有路由
@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"go-web-template/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	// 注册健康检查探针路由（无需认证）
+	RegisterPublic(registerHealthPublicRoutes)
+}
+
+// registerHealthPublicRoutes 注册健康检查探针路由
+func registerHealthPublicRoutes(r *gin.RouterGroup) {
+	healthGroup := r.Group("/health")
+	healthGroup.GET("/live", health.Handler(health.ProbeLiveness))   // 存活探针
+	healthGroup.GET("/ready", health.Handler(health.ProbeReadiness)) // 就绪探针，关闭期间返回 503
+	healthGroup.GET("/startup", health.Handler(health.ProbeStartup)) // 启动探针，启动完成前返回 503
+}
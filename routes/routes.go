@@ -2,6 +2,7 @@ package routes
 
 import (
 	"go-web-template/config"
+	"go-web-template/metrics"
 	"go-web-template/middlewares"
 	"go-web-template/routes/rest"
 
@@ -22,12 +23,24 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 	// 设置 multipart form 内存限制为 100MB
 	r.MaxMultipartMemory = 100 << 20 // 100 MB
 
+	// 健康检查探针与指标不限流
+	unlimited := unlimitedPaths(cfg)
+
 	// 添加自定义中间件
-	r.Use(middlewares.CORS())              // CORS跨域处理（需要在其他中间件之前）
-	r.Use(middlewares.GinLogger())         // 结构化日志
-	r.Use(middlewares.GinRecovery())       // 异常恢复
-	r.Use(middlewares.ErrorLogging())      // 错误响应日志（用于记录逻辑异常）
-	r.Use(middlewares.TracingMiddleware()) // 链路追踪中间件
+	r.Use(middlewares.CORS(&cfg.CORS))                         // CORS跨域处理（需要在其他中间件之前）
+	r.Use(middlewares.RequestLogger())                         // 请求ID与请求级logger（需要在日志、追踪中间件之前）
+	r.Use(middlewares.InFlight())                              // 记录处理中的请求，优雅关闭超时时输出
+	r.Use(middlewares.Metrics(&cfg.Metrics))                   // Prometheus HTTP指标（需要在异常恢复之前，才能统计panic导致的500）
+	r.Use(middlewares.GinLogger(&cfg.Logger.AccessLog))        // 结构化访问日志
+	r.Use(middlewares.GinRecovery())                           // 异常恢复
+	r.Use(middlewares.TracingMiddleware())                     // 链路追踪中间件（需要在限流之前，被限流的请求同样返回 X-Trace-Id）
+	r.Use(middlewares.RateLimit(&cfg.RateLimit, unlimited...)) // 全局限流
+	r.Use(middlewares.ErrorLogging())                          // 错误响应日志（用于记录逻辑异常）
+
+	// Prometheus 指标（未配置单独的管理端口时在应用端口暴露）
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr == "" {
+		r.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
 
 	// API 路由组
 	api := r.Group("/api")
@@ -50,3 +63,12 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 
 	return r
 }
+
+// unlimitedPaths 返回不参与全局限流的请求路径：健康检查探针与应用端口上的指标
+func unlimitedPaths(cfg *config.Config) []string {
+	paths := []string{"/api/health/live", "/api/health/ready", "/api/health/startup"}
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr == "" {
+		paths = append(paths, cfg.Metrics.Path)
+	}
+	return paths
+}
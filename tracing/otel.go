@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go-web-template/buildinfo"
+	"go-web-template/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelInstrumentation 创建 span 使用的 instrumentation 名称
+const otelInstrumentation = "go-web-template"
+
+// otelTracer 基于 OpenTelemetry SDK 的实现
+type otelTracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewOTel 根据配置创建 OpenTelemetry 链路追踪实现，span 通过 OTLP/HTTP 上报或输出到标准输出
+func NewOTel(ctx context.Context, cfg *config.OTelConfig) (Tracer, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("创建OpenTelemetry exporter失败: %w", err)
+	}
+	return NewOTelWithExporter(cfg, exporter), nil
+}
+
+// NewOTelWithExporter 使用指定的 exporter 创建 OpenTelemetry 链路追踪实现，
+// 例如使用 tracetest.InMemoryExporter 在测试中检查生成的 span
+func NewOTelWithExporter(cfg *config.OTelConfig, exporter sdktrace.SpanExporter) Tracer {
+	attrs := []attribute.KeyValue{attribute.String("service.name", cfg.ServiceName)}
+	for k, v := range buildinfo.Tags() {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	)
+
+	// 设置为全局 TracerProvider，使用 OpenTelemetry 埋点的第三方库同样上报到这里
+	otel.SetTracerProvider(provider)
+
+	return &otelTracer{provider: provider, tracer: provider.Tracer(otelInstrumentation)}
+}
+
+// StartSpan 创建 span
+func (t *otelTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	cfg := newSpanConfig(opts)
+
+	options := []trace.SpanStartOption{trace.WithSpanKind(otelKind(cfg.kind))}
+	if cfg.remote != nil {
+		options = append(options, trace.WithAttributes(otelPeerAttributes(cfg.remote)...))
+	}
+	for k, v := range cfg.tags {
+		options = append(options, trace.WithAttributes(attribute.String(k, v)))
+	}
+
+	// 进程内的 span 已通过 trace.ContextWithSpan 保存在上下文中，只需处理上游追踪上下文
+	if SpanFromContext(ctx) == nil {
+		if remote, ok := remoteFromContext(ctx); ok {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, otelSpanContext(remote))
+		}
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, options...)
+	s := &otelSpan{span: span}
+	return ContextWithSpan(ctx, s), s
+}
+
+// Shutdown 上报缓冲中的 span 并关闭 exporter
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+// otelKind 转换 span 类型
+func otelKind(kind Kind) trace.SpanKind {
+	switch kind {
+	case KindServer:
+		return trace.SpanKindServer
+	case KindClient:
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+// otelPeerAttributes 远端服务对应的属性
+func otelPeerAttributes(endpoint *Endpoint) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if endpoint.ServiceName != "" {
+		attrs = append(attrs, attribute.String("peer.service", endpoint.ServiceName))
+	}
+	if endpoint.Host != "" {
+		attrs = append(attrs, attribute.String("net.peer.name", endpoint.Host))
+	}
+	if endpoint.Port != 0 {
+		attrs = append(attrs, attribute.String("net.peer.port", strconv.Itoa(endpoint.Port)))
+	}
+	return attrs
+}
+
+// otelSpanContext 将上游追踪上下文转换为 OpenTelemetry 的 SpanContext，64 位追踪ID高位补零
+func otelSpanContext(sc SpanContext) trace.SpanContext {
+	var traceID trace.TraceID
+	binary.BigEndian.PutUint64(traceID[:8], sc.TraceID.High)
+	binary.BigEndian.PutUint64(traceID[8:], sc.TraceID.Low)
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], uint64(sc.SpanID))
+
+	var flags trace.TraceFlags
+	if sc.IsSampled() {
+		flags = trace.FlagsSampled
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+}
+
+// otelSpan OpenTelemetry span
+type otelSpan struct {
+	span trace.Span
+}
+
+// Context 返回 span 的追踪上下文
+func (s *otelSpan) Context() SpanContext {
+	sc := s.span.SpanContext()
+	traceID := sc.TraceID()
+	spanID := sc.SpanID()
+	sampled := sc.IsSampled()
+	return SpanContext{
+		TraceID: TraceID{
+			High: binary.BigEndian.Uint64(traceID[:8]),
+			Low:  binary.BigEndian.Uint64(traceID[8:]),
+		},
+		SpanID:  SpanID(binary.BigEndian.Uint64(spanID[:])),
+		Sampled: &sampled,
+	}
+}
+
+// Tag 设置属性
+func (s *otelSpan) Tag(key, value string) {
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+// SetError 将 span 状态设置为 Error
+func (s *otelSpan) SetError(message string) {
+	s.span.SetStatus(codes.Error, message)
+}
+
+// Finish 结束 span
+func (s *otelSpan) Finish() {
+	s.span.End()
+}
@@ -0,0 +1,236 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-web-template/config"
+	"go-web-template/database"
+	"go-web-template/middlewares"
+	"go-web-template/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// keepExporter 关闭时保留已导出的 span，InMemoryExporter 的 Shutdown 会清空 span
+type keepExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+// Shutdown 不清空已导出的 span
+func (keepExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// newTestTracer 创建导出到内存的 OpenTelemetry 实现，返回的函数上报并返回所有已结束的 span
+func newTestTracer(t *testing.T) (tracing.Tracer, func() tracetest.SpanStubs) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := tracing.NewOTelWithExporter(&config.OTelConfig{ServiceName: "test", SampleRate: 1}, keepExporter{exporter})
+	return tracer, func() tracetest.SpanStubs {
+		if err := tracer.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		return exporter.GetSpans()
+	}
+}
+
+// useGlobalTracer 设置全局 tracer，测试结束后恢复
+func useGlobalTracer(t *testing.T, tracer tracing.Tracer) {
+	prev := tracing.GetTracer()
+	tracing.SetTracer(tracer)
+	t.Cleanup(func() { tracing.SetTracer(prev) })
+}
+
+// findSpan 按名称查找 span
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("没有名为 %q 的 span，共 %d 个", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+// attribute 返回 span 的字符串属性
+func attribute(span tracetest.SpanStub, key string) string {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+// upstream 上游服务传入的追踪上下文
+var upstream = func() tracing.SpanContext {
+	sampled := true
+	return tracing.SpanContext{
+		TraceID: tracing.TraceID{High: 0x4bf92f3577b34da6, Low: 0xa3ce929d0e0e4736},
+		SpanID:  0x00f067aa0ba902b7,
+		Sampled: &sampled,
+	}
+}()
+
+func TestOTelSpanKindParentAndError(t *testing.T) {
+	tracer, spans := newTestTracer(t)
+
+	ctx := tracing.ContextWithRemote(context.Background(), upstream)
+	ctx, server := tracer.StartSpan(ctx, "server", tracing.WithKind(tracing.KindServer))
+	_, client := tracer.StartSpan(ctx, "client",
+		tracing.WithKind(tracing.KindClient),
+		tracing.WithRemoteEndpoint(tracing.Endpoint{ServiceName: "user-service", Host: "10.0.0.1", Port: 8080}),
+	)
+	client.SetError("boom")
+	client.Finish()
+	server.Finish()
+
+	got := spans()
+	serverSpan := findSpan(t, got, "server")
+	clientSpan := findSpan(t, got, "client")
+
+	if serverSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("server kind = %v, want server", serverSpan.SpanKind)
+	}
+	if clientSpan.SpanKind != trace.SpanKindClient {
+		t.Errorf("client kind = %v, want client", clientSpan.SpanKind)
+	}
+
+	// 上游追踪上下文作为 server span 的远端父 span
+	if got := serverSpan.SpanContext.TraceID().String(); got != upstream.TraceID.String() {
+		t.Errorf("trace id = %s, want %s", got, upstream.TraceID)
+	}
+	if got := serverSpan.Parent.SpanID().String(); got != upstream.SpanID.String() {
+		t.Errorf("server parent = %s, want %s", got, upstream.SpanID)
+	}
+	if !serverSpan.Parent.IsRemote() {
+		t.Error("server parent 应为远端 span")
+	}
+
+	// 进程内的 span 作为 client span 的父 span
+	if clientSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Errorf("client parent = %s, want %s", clientSpan.Parent.SpanID(), serverSpan.SpanContext.SpanID())
+	}
+	if clientSpan.SpanContext.TraceID() != serverSpan.SpanContext.TraceID() {
+		t.Error("client 与 server 应属于同一链路")
+	}
+	if got := server.Context().SpanID.String(); got != serverSpan.SpanContext.SpanID().String() {
+		t.Errorf("Context().SpanID = %s, want %s", got, serverSpan.SpanContext.SpanID())
+	}
+
+	if clientSpan.Status.Code != codes.Error || clientSpan.Status.Description != "boom" {
+		t.Errorf("client status = %+v, want Error boom", clientSpan.Status)
+	}
+	if serverSpan.Status.Code == codes.Error {
+		t.Error("server status 不应为 Error")
+	}
+	if got := attribute(clientSpan, "peer.service"); got != "user-service" {
+		t.Errorf("peer.service = %q, want user-service", got)
+	}
+}
+
+func TestOTelTracingMiddleware(t *testing.T) {
+	tracer, spans := newTestTracer(t)
+	useGlobalTracer(t, tracer)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middlewares.TracingMiddleware())
+	r.GET("/api/items/:id", func(c *gin.Context) {
+		// 处理函数中创建的 span 是请求 span 的子 span
+		_, span := middlewares.StartSpan(c.Request.Context(), "load-item")
+		middlewares.FinishSpan(span, nil)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items/1", nil)
+	req.Header.Set(tracing.TraceparentHeader, tracing.FormatTraceparent(upstream))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get(middlewares.TraceIDHeader); got != upstream.TraceID.String() {
+		t.Errorf("%s = %q, want %q", middlewares.TraceIDHeader, got, upstream.TraceID)
+	}
+
+	got := spans()
+	serverSpan := findSpan(t, got, "GET /api/items/:id")
+	childSpan := findSpan(t, got, "load-item")
+
+	if serverSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("kind = %v, want server", serverSpan.SpanKind)
+	}
+	if got := serverSpan.Parent.SpanID().String(); got != upstream.SpanID.String() {
+		t.Errorf("parent = %s, want %s", got, upstream.SpanID)
+	}
+	if serverSpan.Status.Code != codes.Error {
+		t.Errorf("status = %+v, want Error", serverSpan.Status)
+	}
+	if got := attribute(serverSpan, "http.status_code"); got != "500" {
+		t.Errorf("http.status_code = %q, want 500", got)
+	}
+	if childSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Errorf("load-item parent = %s, want %s", childSpan.Parent.SpanID(), serverSpan.SpanContext.SpanID())
+	}
+}
+
+// userRow 查询结果
+type userRow struct {
+	ID   int
+	Name string
+}
+
+func TestOTelDatabaseTracingPlugin(t *testing.T) {
+	tracer, spans := newTestTracer(t)
+
+	// DryRun 只生成SQL不执行，无需连接数据库
+	dbCfg := &config.DatabaseConfig{Host: "db.local", Port: 5432, DBName: "app"}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=db.local port=5432 dbname=app"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	if err := db.Use(database.NewTracingPlugin(tracer, dbCfg)); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+
+	ctx, parent := tracer.StartSpan(context.Background(), "handler", tracing.WithKind(tracing.KindServer))
+	var rows []userRow
+	db.WithContext(ctx).Table("users").Where("name = ?", "alice").Find(&rows)
+	parent.Finish()
+
+	got := spans()
+	handlerSpan := findSpan(t, got, "handler")
+	dbSpan := findSpan(t, got, "db:query:users")
+
+	if dbSpan.SpanKind != trace.SpanKindClient {
+		t.Errorf("kind = %v, want client", dbSpan.SpanKind)
+	}
+	if dbSpan.Parent.SpanID() != handlerSpan.SpanContext.SpanID() {
+		t.Errorf("parent = %s, want %s", dbSpan.Parent.SpanID(), handlerSpan.SpanContext.SpanID())
+	}
+	tags := map[string]string{
+		"db.system":     "postgresql",
+		"db.name":       "app",
+		"db.operation":  "query",
+		"db.sql.table":  "users",
+		"net.peer.name": "db.local",
+		"net.peer.port": "5432",
+		"db.statement":  `SELECT * FROM "users" WHERE name = $1`,
+	}
+	for key, want := range tags {
+		if got := attribute(dbSpan, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// TraceparentHeader W3C Trace Context 请求头
+const TraceparentHeader = "traceparent"
+
+// Extract 从请求头提取上游的追踪上下文（B3 单头或多头，其次 W3C traceparent），
+// 返回的上下文中创建的 span 作为上游 span 的子 span
+func Extract(ctx context.Context, r *http.Request) context.Context {
+	if sc, err := b3.ExtractHTTP(r)(); err == nil && sc != nil &&
+		(!sc.TraceID.Empty() || sc.Sampled != nil || sc.Debug) {
+		return ContextWithRemote(ctx, fromZipkinContext(*sc))
+	}
+	if sc, ok := ParseTraceparent(r.Header.Get(TraceparentHeader)); ok {
+		return ContextWithRemote(ctx, sc)
+	}
+	return ctx
+}
+
+// Inject 将 span 的追踪上下文以 B3 多头与 W3C traceparent 两种格式注入请求头
+func Inject(span Span, r *http.Request) {
+	sc := span.Context()
+	_ = b3.InjectHTTP(r)(toZipkinContext(sc))
+	r.Header.Set(TraceparentHeader, FormatTraceparent(sc))
+}
+
+// ParseTraceparent 解析 W3C traceparent 请求头，格式为 version-trace_id-parent_id-flags，
+// 例如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+// 返回的 span 上下文以上游 span 为 ID，作为本地 span 的父 span 使用。
+func ParseTraceparent(header string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, false
+	}
+	// 版本 ff 非法；00 版本必须恰好 4 段，更高版本允许追加字段
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+	// 规范要求小写十六进制，strconv.ParseUint 不区分大小写，需单独检查
+	for _, part := range parts[:4] {
+		if !isLowerHex(part) {
+			return SpanContext{}, false
+		}
+	}
+
+	high, err := strconv.ParseUint(parts[1][:16], 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	low, err := strconv.ParseUint(parts[1][16:], 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	traceID := TraceID{High: high, Low: low}
+	if traceID.Empty() {
+		return SpanContext{}, false
+	}
+	spanID, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil || spanID == 0 {
+		return SpanContext{}, false
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	sampled := flags&0x01 == 0x01
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  SpanID(spanID),
+		Sampled: &sampled,
+	}, true
+}
+
+// isLowerHex 判断字符串是否只包含小写十六进制字符
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatTraceparent 生成 W3C traceparent 请求头
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.IsSampled() {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%016x%016x-%016x-%s", sc.TraceID.High, sc.TraceID.Low, uint64(sc.SpanID), flags)
+}
@@ -0,0 +1,205 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+)
+
+// TraceID 128 位追踪ID，上游使用 64 位追踪ID时 High 为 0
+type TraceID struct {
+	High uint64
+	Low  uint64
+}
+
+// Empty 追踪ID是否为空
+func (t TraceID) Empty() bool {
+	return t.High == 0 && t.Low == 0
+}
+
+// String 返回十六进制表示，64 位追踪ID为 16 个字符，128 位为 32 个字符
+func (t TraceID) String() string {
+	if t.High == 0 {
+		return fmt.Sprintf("%016x", t.Low)
+	}
+	return fmt.Sprintf("%016x%016x", t.High, t.Low)
+}
+
+// SpanID span ID
+type SpanID uint64
+
+// String 返回 16 个字符的十六进制表示
+func (id SpanID) String() string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
+
+// SpanContext 追踪上下文，与具体实现无关
+//
+// B3 与 W3C traceparent 请求头的提取和注入基于该结构，各实现自行与其内部表示相互转换。
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Sampled 是否采样，nil 表示上游未决定
+	Sampled *bool
+	// Debug 强制采样（B3 的 X-B3-Flags: 1）
+	Debug bool
+}
+
+// IsSampled 是否采样，Debug 视为采样
+func (sc SpanContext) IsSampled() bool {
+	return sc.Debug || (sc.Sampled != nil && *sc.Sampled)
+}
+
+// Kind span 类型
+type Kind int
+
+const (
+	// KindInternal 进程内操作
+	KindInternal Kind = iota
+	// KindServer 处理入站请求
+	KindServer
+	// KindClient 发起出站调用（HTTP、数据库等）
+	KindClient
+)
+
+// Endpoint 出站调用的远端服务
+type Endpoint struct {
+	ServiceName string
+	Host        string
+	Port        int
+}
+
+// Span 一次操作的追踪记录
+type Span interface {
+	// Context 返回 span 的追踪上下文
+	Context() SpanContext
+	// Tag 设置标签
+	Tag(key, value string)
+	// SetError 将 span 标记为失败并记录错误信息
+	SetError(message string)
+	// Finish 结束 span
+	Finish()
+}
+
+// Tracer 链路追踪实现，目前有 Zipkin 与 OpenTelemetry 两种
+type Tracer interface {
+	// StartSpan 创建 span，ctx 中已有 span 或上游追踪上下文时作为其子 span，
+	// 返回的上下文携带新建的 span
+	StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span)
+	// Shutdown 上报缓冲中的 span 并释放资源
+	Shutdown(ctx context.Context) error
+}
+
+// SpanOption 创建 span 的选项
+type SpanOption func(*spanConfig)
+
+// spanConfig 创建 span 的配置
+type spanConfig struct {
+	kind   Kind
+	remote *Endpoint
+	tags   map[string]string
+	route  string
+}
+
+// newSpanConfig 应用创建 span 的选项
+func newSpanConfig(opts []SpanOption) *spanConfig {
+	cfg := &spanConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithKind 设置 span 类型
+func WithKind(kind Kind) SpanOption {
+	return func(cfg *spanConfig) {
+		cfg.kind = kind
+	}
+}
+
+// WithRemoteEndpoint 设置出站调用的远端服务
+func WithRemoteEndpoint(endpoint Endpoint) SpanOption {
+	return func(cfg *spanConfig) {
+		cfg.remote = &endpoint
+	}
+}
+
+// WithRoute 设置入站请求的路径，供按请求路径的采样规则匹配
+func WithRoute(path string) SpanOption {
+	return func(cfg *spanConfig) {
+		cfg.route = path
+	}
+}
+
+// WithTags 创建 span 时设置标签
+func WithTags(tags map[string]string) SpanOption {
+	return func(cfg *spanConfig) {
+		if cfg.tags == nil {
+			cfg.tags = make(map[string]string, len(tags))
+		}
+		for k, v := range tags {
+			cfg.tags[k] = v
+		}
+	}
+}
+
+var tracer Tracer
+
+// SetTracer 设置全局 tracer，nil 表示不启用链路追踪
+func SetTracer(t Tracer) {
+	tracer = t
+}
+
+// GetTracer 获取全局 tracer，未启用链路追踪时返回 nil
+func GetTracer() Tracer {
+	return tracer
+}
+
+// Enabled 是否启用了链路追踪
+func Enabled() bool {
+	return tracer != nil
+}
+
+// StartSpan 使用全局 tracer 创建 span，未启用链路追踪时返回原上下文和 nil
+func StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, nil
+	}
+	return tracer.StartSpan(ctx, name, opts...)
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// ContextWithSpan 返回携带 span 的上下文
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext 获取上下文中的 span，没有时返回 nil
+func SpanFromContext(ctx context.Context) Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+// ContextWithRemote 返回携带上游追踪上下文的上下文，之后创建的 span 作为其子 span
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// remoteFromContext 获取上下文中的上游追踪上下文
+func remoteFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, ok
+}
+
+// parentFromContext 获取新建 span 的父 span 上下文，进程内的 span 优先于上游追踪上下文
+func parentFromContext(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.Context(), true
+	}
+	return remoteFromContext(ctx)
+}
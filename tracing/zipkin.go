@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"context"
+	"net"
+
+	"go-web-template/utils"
+
+	"github.com/openzipkin/zipkin-go"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+// zipkinTracer 基于 zipkin-go 的实现
+type zipkinTracer struct {
+	zipkin  *utils.Zipkin
+	tracer  *zipkin.Tracer
+	sampler *utils.ZipkinSampler
+}
+
+// NewZipkin 使用 utils.InitZipkin 的结果创建链路追踪实现，入站请求的采样由采样器按请求路径和上游的决定确定
+func NewZipkin(z *utils.Zipkin) Tracer {
+	return &zipkinTracer{zipkin: z, tracer: z.Tracer, sampler: z.Sampler}
+}
+
+// StartSpan 创建 span
+func (t *zipkinTracer) StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span) {
+	cfg := newSpanConfig(opts)
+
+	var options []zipkin.SpanOption
+	switch cfg.kind {
+	case KindServer:
+		options = append(options, zipkin.Kind(model.Server))
+	case KindClient:
+		options = append(options, zipkin.Kind(model.Client))
+	}
+	if cfg.remote != nil {
+		options = append(options, zipkin.RemoteEndpoint(zipkinEndpoint(cfg.remote)))
+	}
+	if len(cfg.tags) > 0 {
+		options = append(options, zipkin.Tags(cfg.tags))
+	}
+
+	// 进程内的父 span 使用 zipkin-go 的追踪上下文，延迟决定的链路在本地按采样记录
+	var (
+		parent    model.SpanContext
+		hasParent bool
+		unsampled bool
+	)
+	if local, ok := SpanFromContext(ctx).(*zipkinSpan); ok {
+		parent, hasParent = local.span.Context(), true
+		unsampled = local.unsampled
+	} else if sc, ok := parentFromContext(ctx); ok {
+		parent, hasParent = toZipkinContext(sc), true
+	}
+
+	// 入站请求（新链路或上游传入的链路）由采样器决定，进程内的子 span 沿用父 span 的决定
+	deferred := false
+	if cfg.kind == KindServer && t.sampler != nil && SpanFromContext(ctx) == nil && !parent.Debug {
+		decision := t.sampler.Decide(cfg.route, parent.Sampled)
+		sampled := decision.Sampled || decision.Deferred
+		parent.Sampled = &sampled
+		hasParent = true
+		deferred = decision.Deferred
+		unsampled = decision.Deferred
+	}
+	if hasParent {
+		// 追踪ID为空时 zipkin-go 创建新链路，并沿用这里给出的采样决定
+		options = append(options, zipkin.Parent(parent))
+	}
+
+	span := t.tracer.StartSpan(name, options...)
+	s := &zipkinSpan{span: span, unsampled: unsampled}
+	if deferred {
+		s.sampler = t.sampler
+		t.sampler.Defer(span.Context().TraceID)
+	}
+
+	// 同时以 zipkin-go 的方式保存，便于直接使用 zipkin-go 的代码获取当前 span
+	ctx = zipkin.NewContext(ctx, span)
+	return ContextWithSpan(ctx, s), s
+}
+
+// Shutdown 上报缓冲中的 span 并关闭 reporter
+func (t *zipkinTracer) Shutdown(ctx context.Context) error {
+	return t.zipkin.Close(ctx)
+}
+
+// toZipkinContext 转换为 zipkin-go 的追踪上下文
+func toZipkinContext(sc SpanContext) model.SpanContext {
+	return model.SpanContext{
+		TraceID: model.TraceID{High: sc.TraceID.High, Low: sc.TraceID.Low},
+		ID:      model.ID(sc.SpanID),
+		Sampled: sc.Sampled,
+		Debug:   sc.Debug,
+	}
+}
+
+// fromZipkinContext 将 zipkin-go 的追踪上下文转换为 SpanContext
+func fromZipkinContext(sc model.SpanContext) SpanContext {
+	return SpanContext{
+		TraceID: TraceID{High: sc.TraceID.High, Low: sc.TraceID.Low},
+		SpanID:  SpanID(sc.ID),
+		Sampled: sc.Sampled,
+		Debug:   sc.Debug,
+	}
+}
+
+// zipkinEndpoint 将远端服务转换为 Zipkin 的 Endpoint，主机为IP地址时填入 IPv4/IPv6
+func zipkinEndpoint(endpoint *Endpoint) *model.Endpoint {
+	remote := &model.Endpoint{ServiceName: endpoint.ServiceName, Port: uint16(endpoint.Port)}
+	if ip := net.ParseIP(endpoint.Host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			remote.IPv4 = ip4
+		} else {
+			remote.IPv6 = ip
+		}
+	}
+	return remote
+}
+
+// zipkinSpan Zipkin span
+type zipkinSpan struct {
+	span zipkin.Span
+
+	// sampler 不为空时链路延迟决定是否上报，结束时以错误结束才上报
+	sampler *utils.ZipkinSampler
+	failed  bool
+
+	// unsampled 链路延迟决定是否上报：本地按采样记录，向下游传递时标记为未采样，
+	// 避免下游服务上报本服务最终可能丢弃的链路
+	unsampled bool
+}
+
+// Context 返回 span 的追踪上下文，即向下游传递的追踪上下文
+func (s *zipkinSpan) Context() SpanContext {
+	sc := fromZipkinContext(s.span.Context())
+	if s.unsampled {
+		sampled := false
+		sc.Sampled = &sampled
+	}
+	return sc
+}
+
+// Tag 设置标签
+func (s *zipkinSpan) Tag(key, value string) {
+	s.span.Tag(key, value)
+}
+
+// SetError 设置 error 标签
+func (s *zipkinSpan) SetError(message string) {
+	zipkin.TagError.Set(s.span, message)
+	s.failed = true
+}
+
+// Finish 结束 span
+func (s *zipkinSpan) Finish() {
+	s.span.Finish()
+	if s.sampler != nil {
+		s.sampler.Resolve(s.span.Context().TraceID, s.failed)
+	}
+}
@@ -0,0 +1,96 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-web-template/config"
+	"go-web-template/middlewares"
+	"go-web-template/tracing"
+	"go-web-template/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openzipkin/zipkin-go"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	"go.uber.org/zap"
+)
+
+// newTestZipkin 创建上报到内存的 Zipkin 实现
+func newTestZipkin(t *testing.T, cfg config.ZipkinConfig) (tracing.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+	sampler := utils.NewZipkinSampler(&cfg, zap.NewNop())
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(sampler.Reporter(rec), zipkin.WithSampler(sampler.Sample))
+	if err != nil {
+		t.Fatalf("NewTracer: %v", err)
+	}
+	return tracing.NewZipkin(&utils.Zipkin{Tracer: tracer, Sampler: sampler}), rec
+}
+
+// newPanicRouter 创建与 routes 相同顺序（异常恢复在链路追踪外层）的路由，/panic 处理时 panic
+func newPanicRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middlewares.GinRecovery())
+	r.Use(middlewares.TracingMiddleware())
+	r.GET("/api/private/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	r.GET("/api/private/fail", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	r.GET("/api/private/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestZipkinTracingMiddlewarePanic(t *testing.T) {
+	tracer, rec := newTestZipkin(t, config.ZipkinConfig{Sampler: utils.SamplerAlways})
+	useGlobalTracer(t, tracer)
+
+	w := httptest.NewRecorder()
+	newPanicRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/private/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("上报 %d 个 span, want 1", len(spans))
+	}
+	if got := spans[0].Tags["http.status_code"]; got != "500" {
+		t.Errorf("http.status_code = %q, want 500", got)
+	}
+	if got := spans[0].Tags["error"]; got != "panic: boom" {
+		t.Errorf("error = %q, want %q", got, "panic: boom")
+	}
+}
+
+func TestZipkinDeferredErrorsPanic(t *testing.T) {
+	tracer, rec := newTestZipkin(t, config.ZipkinConfig{
+		Sampler: utils.SamplerNever,
+		Rules: []config.ZipkinSamplingRule{
+			{Path: "/api/private/*", Errors: true},
+		},
+	})
+	useGlobalTracer(t, tracer)
+	r := newPanicRouter()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/private/ok", want: 0},
+		{path: "/api/private/fail", want: 1},
+		// panic 由外层的异常恢复中间件处理，同样以错误结束
+		{path: "/api/private/panic", want: 1},
+	}
+	for _, tt := range tests {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got := len(rec.Flush()); got != tt.want {
+			t.Errorf("%s: 上报 %d 个 span, want %d", tt.path, got, tt.want)
+		}
+	}
+}
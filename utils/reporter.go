@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-web-template/config"
+
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter"
+	zipkinhttp "github.com/openzipkin/zipkin-go/reporter/http"
+	"go.uber.org/zap"
+)
+
+// ZipkinReporter 批量上报 span 到 Zipkin，统计上报成功与丢弃的 span 数
+//
+// zipkin-go 的 HTTP reporter 不提供统计接口：积压超过 max_backlog 时丢弃最早的 span 并打印日志，
+// Zipkin 返回非 2xx 时丢弃整批 span，网络错误时保留批次下次重试。
+// 这里通过包装序列化器、HTTP 客户端和日志分别统计这几种情况。
+type ZipkinReporter struct {
+	reporter reporter.Reporter
+	logger   *zap.Logger
+
+	// mu 保证关闭后不再向已停止的 reporter 发送 span（zipkin-go 的 Send 会一直阻塞）
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+
+	received atomic.Uint64
+	reported atomic.Uint64
+	dropped  atomic.Uint64
+
+	// pending 已提交给 zipkin-go、尚未上报成功或被丢弃的 span 数
+	pending atomic.Int64
+
+	// batch 最近一次序列化的批次大小，zipkin-go 在同一个 goroutine 中依次序列化和发送批次
+	batch atomic.Int64
+}
+
+// ZipkinReporterStats span 上报统计
+type ZipkinReporterStats struct {
+	Received uint64 // 已接收的 span 数
+	Reported uint64 // 上报成功的 span 数
+	Dropped  uint64 // 因积压、Zipkin 拒绝、关闭时最后一批上报失败或 reporter 已关闭而丢弃的 span 数
+}
+
+// NewZipkinReporter 创建 span 上报器
+func NewZipkinReporter(endpoint string, cfg *config.ZipkinReporterConfig, logger *zap.Logger) *ZipkinReporter {
+	r := &ZipkinReporter{logger: logger, done: make(chan struct{})}
+	r.reporter = zipkinhttp.NewReporter(endpoint,
+		zipkinhttp.BatchSize(cfg.BatchSize),
+		zipkinhttp.BatchInterval(time.Duration(cfg.BatchInterval)*time.Millisecond),
+		zipkinhttp.MaxBacklog(cfg.MaxBacklog),
+		zipkinhttp.Timeout(time.Duration(cfg.Timeout)*time.Millisecond),
+		zipkinhttp.Serializer(&countingSerializer{SpanSerializer: reporter.JSONSerializer{}, reporter: r}),
+		zipkinhttp.Client(&countingClient{client: &http.Client{}, reporter: r}),
+		zipkinhttp.Logger(log.New(&reporterLogWriter{reporter: r}, "", 0)),
+	)
+	return r
+}
+
+// Send 提交 span，reporter 关闭后直接丢弃
+func (r *ZipkinReporter) Send(span model.SpanModel) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		r.dropped.Add(1)
+		return
+	}
+	r.received.Add(1)
+	r.pending.Add(1)
+	r.reporter.Send(span)
+}
+
+// Close 上报缓冲中的 span 并停止 reporter，记录最终的上报统计
+//
+// 最后一批上报失败（如网络错误）时 zipkin-go 不再重试，未上报的 span 计入丢弃。
+func (r *ZipkinReporter) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	r.mu.Unlock()
+
+	err := r.reporter.Close()
+	if lost := r.pending.Swap(0); lost > 0 {
+		r.dropped.Add(uint64(lost))
+	}
+
+	stats := r.Stats()
+	fields := []zap.Field{
+		zap.Uint64("received", stats.Received),
+		zap.Uint64("reported", stats.Reported),
+		zap.Uint64("dropped", stats.Dropped),
+	}
+	if err != nil || stats.Dropped > 0 {
+		r.logger.Warn("Zipkin reporter已关闭，有span未上报", append(fields, zap.Error(err))...)
+	} else {
+		r.logger.Info("Zipkin reporter已关闭", fields...)
+	}
+	return err
+}
+
+// Stats 返回 span 上报统计
+func (r *ZipkinReporter) Stats() ZipkinReporterStats {
+	return ZipkinReporterStats{
+		Received: r.received.Load(),
+		Reported: r.reported.Load(),
+		Dropped:  r.dropped.Load(),
+	}
+}
+
+// Done reporter 关闭时关闭的通道
+func (r *ZipkinReporter) Done() <-chan struct{} {
+	return r.done
+}
+
+// countingSerializer 记录每批 span 的数量
+type countingSerializer struct {
+	reporter.SpanSerializer
+	reporter *ZipkinReporter
+}
+
+// Serialize 序列化一批 span
+func (s *countingSerializer) Serialize(spans []*model.SpanModel) ([]byte, error) {
+	s.reporter.batch.Store(int64(len(spans)))
+	return s.SpanSerializer.Serialize(spans)
+}
+
+// countingClient 按上报结果统计成功与丢弃的 span 数
+type countingClient struct {
+	client   *http.Client
+	reporter *ZipkinReporter
+}
+
+// Do 发送一批 span，网络错误时 zipkin-go 保留批次重试，不计入丢弃
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	n := uint64(c.reporter.batch.Load())
+	c.reporter.pending.Add(-int64(n))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.reporter.dropped.Add(n)
+	} else {
+		c.reporter.reported.Add(n)
+	}
+	return resp, nil
+}
+
+// reporterLogWriter 将 zipkin-go reporter 的日志写入 zap，并统计因积压丢弃的 span 数
+type reporterLogWriter struct {
+	reporter *ZipkinReporter
+}
+
+// Write 写入一行日志
+func (w *reporterLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+
+	var disposed uint64
+	if _, err := fmt.Sscanf(msg, "backlog too long, disposing %d spans", &disposed); err == nil {
+		w.reporter.dropped.Add(disposed)
+		w.reporter.pending.Add(-int64(disposed))
+	}
+
+	w.reporter.logger.Warn("Zipkin reporter异常", zap.String("detail", msg))
+	return len(p), nil
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-web-template/config"
+
+	"github.com/openzipkin/zipkin-go/model"
+	"go.uber.org/zap"
+)
+
+// newTestReporter 创建上报到 endpoint 的 reporter，批次间隔足够长，span 只在关闭时上报
+func newTestReporter(endpoint string) *ZipkinReporter {
+	return NewZipkinReporter(endpoint, &config.ZipkinReporterConfig{
+		BatchSize:     100,
+		BatchInterval: 60000,
+		MaxBacklog:    1000,
+		Timeout:       1000,
+	}, zap.NewNop())
+}
+
+// sendSpans 提交 n 个 span
+func sendSpans(r *ZipkinReporter, n int) {
+	for i := 1; i <= n; i++ {
+		r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: model.ID(i)}})
+	}
+}
+
+func TestZipkinReporterCloseFlushes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	r := newTestReporter(server.URL)
+	sendSpans(r, 3)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := r.Stats(); got != (ZipkinReporterStats{Received: 3, Reported: 3}) {
+		t.Errorf("Stats = %+v, want 3 个上报成功", got)
+	}
+}
+
+func TestZipkinReporterCloseCountsLostSpans(t *testing.T) {
+	// 关闭的服务器：最后一批上报时返回网络错误
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	r := newTestReporter(server.URL)
+	sendSpans(r, 3)
+	if err := r.Close(); err == nil {
+		t.Fatal("Close 应返回上报失败的错误")
+	}
+	if got := r.Stats(); got != (ZipkinReporterStats{Received: 3, Dropped: 3}) {
+		t.Errorf("Stats = %+v, want 3 个丢弃", got)
+	}
+
+	// 关闭后提交的 span 直接丢弃
+	sendSpans(r, 1)
+	if got := r.Stats().Dropped; got != 4 {
+		t.Errorf("关闭后 Dropped = %d, want 4", got)
+	}
+}
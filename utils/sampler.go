@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-web-template/config"
+
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter"
+	"go.uber.org/zap"
+)
+
+// 采样策略
+const (
+	SamplerProbability = "probability"
+	SamplerAlways      = "always"
+	SamplerNever       = "never"
+)
+
+// maxDeferredSpans 延迟决定的链路最多缓存的 span 数，超出部分直接丢弃
+const maxDeferredSpans = 1000
+
+// resolvedTTL 延迟决定的链路结束后保留决定的时间，期间结束的异步子 span 按同一决定上报或丢弃
+const resolvedTTL = 30 * time.Second
+
+// countingSampler 计数采样器，按调用次数精确采样 rate 比例的链路，例如 0.3 每 10 次恰好采样 3 次
+//
+// 与按追踪ID取模不同，采样率不会被截断（0.3 取模会变成 1/3），也不依赖追踪ID的随机性。
+type countingSampler struct {
+	rate  float64
+	count atomic.Uint64
+}
+
+// sample 第 n 次调用时，当 n*rate 的整数部分比 (n-1)*rate 大时采样
+func (s *countingSampler) sample() bool {
+	n := s.count.Add(1)
+	return uint64(float64(n)*s.rate) != uint64(float64(n-1)*s.rate)
+}
+
+// newStrategy 创建采样策略对应的采样函数
+func newStrategy(sampler string, rate float64) func() bool {
+	switch sampler {
+	case SamplerAlways:
+		return func() bool { return true }
+	case SamplerNever:
+		return func() bool { return false }
+	default:
+		return (&countingSampler{rate: rate}).sample
+	}
+}
+
+// samplingRule 按请求路径的采样规则
+type samplingRule struct {
+	path   string
+	prefix bool
+	sample func() bool
+	errors bool
+}
+
+// match 判断请求路径是否匹配规则
+func (r *samplingRule) match(path string) bool {
+	if r.prefix {
+		return strings.HasPrefix(path, r.path)
+	}
+	return path == r.path
+}
+
+// samplerState 当前生效的采样配置，配置热加载时整体替换
+type samplerState struct {
+	sample      func() bool
+	parentBased bool
+	rules       []samplingRule
+}
+
+// newSamplerState 根据配置创建采样配置
+func newSamplerState(cfg *config.ZipkinConfig) *samplerState {
+	state := &samplerState{
+		sample:      newStrategy(cfg.Sampler, cfg.SampleRate),
+		parentBased: cfg.ParentBased,
+	}
+	for _, r := range cfg.Rules {
+		rule := samplingRule{path: r.Path, errors: r.Errors}
+		if strings.HasSuffix(r.Path, "*") {
+			rule.path = strings.TrimSuffix(r.Path, "*")
+			rule.prefix = true
+		}
+		if r.Sampler != "" {
+			rule.sample = newStrategy(r.Sampler, r.SampleRate)
+		}
+		state.rules = append(state.rules, rule)
+	}
+	return state
+}
+
+// decide 按全局策略决定是否采样，parentBased 时沿用上游的决定
+func (s *samplerState) decide(parent *bool) bool {
+	if s.parentBased && parent != nil {
+		return *parent
+	}
+	return s.sample()
+}
+
+// SamplingDecision 采样决定
+type SamplingDecision struct {
+	// Sampled 是否采样
+	Sampled bool
+	// Deferred 未采样但以错误结束时仍需上报：链路按采样记录，span 先缓存，请求结束时由 Resolve 决定上报或丢弃
+	Deferred bool
+}
+
+// ZipkinSampler Zipkin采样器
+//
+// 支持全局的计数概率采样、全部采样与不采样，沿用上游的采样决定（parent-based），
+// 以及按请求路径的规则（例如从不采样 /api/ping、总是上报 /api/private/* 的错误请求）。
+type ZipkinSampler struct {
+	state    atomic.Pointer[samplerState]
+	reporter reporter.Reporter
+	deferred sync.Map // model.TraceID -> *deferredTrace
+}
+
+// NewZipkinSampler 根据配置创建Zipkin采样器，配置热加载时同步更新采样策略
+func NewZipkinSampler(cfg *config.ZipkinConfig, logger *zap.Logger) *ZipkinSampler {
+	s := &ZipkinSampler{}
+	s.state.Store(newSamplerState(cfg))
+
+	config.OnChange(func(prev, next *config.Config) {
+		if prev.Zipkin.Sampler == next.Zipkin.Sampler &&
+			prev.Zipkin.SampleRate == next.Zipkin.SampleRate &&
+			prev.Zipkin.ParentBased == next.Zipkin.ParentBased &&
+			reflect.DeepEqual(prev.Zipkin.Rules, next.Zipkin.Rules) {
+			return
+		}
+		s.state.Store(newSamplerState(&next.Zipkin))
+		logger.Info("Zipkin采样策略已更新",
+			zap.String("sampler", next.Zipkin.Sampler),
+			zap.Float64("sample_rate", next.Zipkin.SampleRate),
+			zap.Bool("parent_based", next.Zipkin.ParentBased),
+			zap.Int("rules", len(next.Zipkin.Rules)),
+		)
+	})
+
+	return s
+}
+
+// Sample 按全局采样策略决定新链路是否采样，可作为 zipkin.Sampler 使用
+func (s *ZipkinSampler) Sample(uint64) bool {
+	return s.state.Load().sample()
+}
+
+// Decide 决定入站请求是否采样，path 为请求路径，parent 为上游的采样决定（没有时为 nil）
+//
+// 使用第一条匹配的规则：规则指定了采样策略时忽略上游的决定，否则按全局策略。
+func (s *ZipkinSampler) Decide(path string, parent *bool) SamplingDecision {
+	state := s.state.Load()
+	for i := range state.rules {
+		rule := &state.rules[i]
+		if !rule.match(path) {
+			continue
+		}
+		var sampled bool
+		if rule.sample != nil {
+			sampled = rule.sample()
+		} else {
+			sampled = state.decide(parent)
+		}
+		return SamplingDecision{Sampled: sampled, Deferred: !sampled && rule.errors}
+	}
+	return SamplingDecision{Sampled: state.decide(parent)}
+}
+
+// Reporter 包装 reporter：延迟决定的链路的 span 先缓存，其余直接上报
+func (s *ZipkinSampler) Reporter(next reporter.Reporter) reporter.Reporter {
+	s.reporter = next
+	return &deferringReporter{Reporter: next, sampler: s}
+}
+
+// Defer 开始缓存链路的 span
+func (s *ZipkinSampler) Defer(traceID model.TraceID) {
+	s.deferred.LoadOrStore(traceID, &deferredTrace{})
+}
+
+// Resolve 结束缓存，keep 为 true 时上报缓存的 span，否则丢弃
+//
+// 决定在 resolvedTTL 内保留，之后结束的 span（如请求返回后仍在运行的异步任务）按同一决定处理。
+func (s *ZipkinSampler) Resolve(traceID model.TraceID, keep bool) {
+	val, ok := s.deferred.Load(traceID)
+	if !ok {
+		return
+	}
+	trace := val.(*deferredTrace)
+	trace.mu.Lock()
+	if trace.resolved {
+		trace.mu.Unlock()
+		return
+	}
+	trace.resolved, trace.keep = true, keep
+	spans := trace.spans
+	trace.spans = nil
+	trace.mu.Unlock()
+
+	time.AfterFunc(resolvedTTL, func() {
+		s.deferred.CompareAndDelete(traceID, trace)
+	})
+
+	if !keep || s.reporter == nil {
+		return
+	}
+	for _, span := range spans {
+		s.reporter.Send(span)
+	}
+}
+
+// deferredTrace 延迟决定的链路已结束的 span，resolved 后按 keep 直接上报或丢弃
+type deferredTrace struct {
+	mu       sync.Mutex
+	spans    []model.SpanModel
+	resolved bool
+	keep     bool
+}
+
+// deferringReporter 缓存延迟决定的链路的 span
+type deferringReporter struct {
+	reporter.Reporter
+	sampler *ZipkinSampler
+}
+
+// Send 上报 span，延迟决定的链路在 Resolve 之前先缓存，之后按决定上报或丢弃
+func (r *deferringReporter) Send(span model.SpanModel) {
+	val, ok := r.sampler.deferred.Load(span.TraceID)
+	if !ok {
+		r.Reporter.Send(span)
+		return
+	}
+	trace := val.(*deferredTrace)
+	trace.mu.Lock()
+	if !trace.resolved {
+		if len(trace.spans) < maxDeferredSpans {
+			trace.spans = append(trace.spans, span)
+		}
+		trace.mu.Unlock()
+		return
+	}
+	keep := trace.keep
+	trace.mu.Unlock()
+	if keep {
+		r.Reporter.Send(span)
+	}
+}
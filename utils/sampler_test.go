@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+
+	"go-web-template/config"
+
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	"go.uber.org/zap"
+)
+
+// newTestSampler 根据配置创建采样器
+func newTestSampler(cfg config.ZipkinConfig) *ZipkinSampler {
+	return NewZipkinSampler(&cfg, zap.NewNop())
+}
+
+// countSampled 调用 n 次采样函数，返回采样的次数
+func countSampled(n int, sample func() bool) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		if sample() {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestCountingSamplerRates(t *testing.T) {
+	tests := []struct {
+		rate float64
+		n    int
+		want int
+	}{
+		{rate: 0.3, n: 10, want: 3},
+		{rate: 0.3, n: 1000, want: 300},
+		{rate: 0.5, n: 1000, want: 500},
+		{rate: 1, n: 1000, want: 1000},
+		{rate: 0.001, n: 10000, want: 10},
+		{rate: 0, n: 1000, want: 0},
+	}
+	for _, tt := range tests {
+		s := newTestSampler(config.ZipkinConfig{Sampler: SamplerProbability, SampleRate: tt.rate})
+		got := countSampled(tt.n, func() bool { return s.Sample(0) })
+		if got != tt.want {
+			t.Errorf("rate %g: %d 次中采样 %d 次, want %d", tt.rate, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCountingSamplerIsEvenlySpread(t *testing.T) {
+	// 0.3 在每 10 次调用中都恰好采样 3 次，而不是集中在开头
+	s := newTestSampler(config.ZipkinConfig{Sampler: SamplerProbability, SampleRate: 0.3})
+	for window := 0; window < 100; window++ {
+		if got := countSampled(10, func() bool { return s.Sample(0) }); got != 3 {
+			t.Fatalf("第 %d 个 10 次调用中采样 %d 次, want 3", window, got)
+		}
+	}
+}
+
+func TestAlwaysAndNeverSamplers(t *testing.T) {
+	always := newTestSampler(config.ZipkinConfig{Sampler: SamplerAlways, SampleRate: 0})
+	if got := countSampled(100, func() bool { return always.Sample(0) }); got != 100 {
+		t.Errorf("always: 采样 %d 次, want 100", got)
+	}
+	never := newTestSampler(config.ZipkinConfig{Sampler: SamplerNever, SampleRate: 1})
+	if got := countSampled(100, func() bool { return never.Sample(0) }); got != 0 {
+		t.Errorf("never: 采样 %d 次, want 0", got)
+	}
+}
+
+func TestParentBasedSampling(t *testing.T) {
+	sampled, unsampled := true, false
+
+	tests := []struct {
+		name        string
+		sampler     string
+		parentBased bool
+		parent      *bool
+		want        bool
+	}{
+		{name: "沿用上游的采样", sampler: SamplerNever, parentBased: true, parent: &sampled, want: true},
+		{name: "沿用上游的不采样", sampler: SamplerAlways, parentBased: true, parent: &unsampled, want: false},
+		{name: "上游未决定时按全局策略", sampler: SamplerAlways, parentBased: true, parent: nil, want: true},
+		{name: "关闭 parent_based 时忽略上游的采样", sampler: SamplerNever, parentBased: false, parent: &sampled, want: false},
+		{name: "关闭 parent_based 时忽略上游的不采样", sampler: SamplerAlways, parentBased: false, parent: &unsampled, want: true},
+	}
+	for _, tt := range tests {
+		s := newTestSampler(config.ZipkinConfig{Sampler: tt.sampler, ParentBased: tt.parentBased})
+		decision := s.Decide("/api/users", tt.parent)
+		if decision.Sampled != tt.want || decision.Deferred {
+			t.Errorf("%s: %+v, want Sampled=%v", tt.name, decision, tt.want)
+		}
+	}
+}
+
+func TestRouteRules(t *testing.T) {
+	sampled := true
+	s := newTestSampler(config.ZipkinConfig{
+		Sampler:     SamplerNever,
+		ParentBased: true,
+		Rules: []config.ZipkinSamplingRule{
+			{Path: "/api/ping", Sampler: SamplerNever},
+			{Path: "/api/*", Sampler: SamplerAlways},
+			{Path: "/api/users", Sampler: SamplerNever},
+			{Path: "/static/*"},
+		},
+	})
+
+	tests := []struct {
+		path   string
+		parent *bool
+		want   bool
+	}{
+		// 第一条匹配的规则生效，/api/ping 同时匹配 /api/*
+		{path: "/api/ping", want: false},
+		// /api/* 在 /api/users 之前，后面的规则不生效
+		{path: "/api/users", want: true},
+		{path: "/api/orders/1", want: true},
+		// 规则指定了采样策略时忽略上游的决定
+		{path: "/api/ping", parent: &sampled, want: false},
+		// 前缀规则不匹配前缀之外的路径
+		{path: "/apiv2/users", want: false},
+		// 规则未指定采样策略时按全局策略（含 parent_based）
+		{path: "/static/app.js", want: false},
+		{path: "/static/app.js", parent: &sampled, want: true},
+		// 没有匹配的规则时按全局策略
+		{path: "/health", want: false},
+	}
+	for _, tt := range tests {
+		if got := s.Decide(tt.path, tt.parent); got.Sampled != tt.want {
+			t.Errorf("Decide(%q, parent=%v) = %+v, want Sampled=%v", tt.path, tt.parent, got, tt.want)
+		}
+	}
+}
+
+func TestRouteRuleRates(t *testing.T) {
+	s := newTestSampler(config.ZipkinConfig{
+		Sampler:    SamplerAlways,
+		SampleRate: 1,
+		Rules: []config.ZipkinSamplingRule{
+			{Path: "/api/search", Sampler: SamplerProbability, SampleRate: 0.3},
+		},
+	})
+	if got := countSampled(1000, func() bool { return s.Decide("/api/search", nil).Sampled }); got != 300 {
+		t.Errorf("/api/search: 1000 次中采样 %d 次, want 300", got)
+	}
+	if got := countSampled(1000, func() bool { return s.Decide("/api/users", nil).Sampled }); got != 1000 {
+		t.Errorf("/api/users: 1000 次中采样 %d 次, want 1000", got)
+	}
+}
+
+func TestDeferredErrorsSampling(t *testing.T) {
+	s := newTestSampler(config.ZipkinConfig{
+		Sampler: SamplerNever,
+		Rules: []config.ZipkinSamplingRule{
+			{Path: "/api/private/*", Errors: true},
+		},
+	})
+	rec := recorder.NewReporter()
+	r := s.Reporter(rec)
+
+	if got := s.Decide("/api/private/orders", nil); got.Sampled || !got.Deferred {
+		t.Fatalf("Decide = %+v, want Deferred", got)
+	}
+	if got := s.Decide("/api/public", nil); got.Sampled || got.Deferred {
+		t.Fatalf("Decide(/api/public) = %+v, want 不采样且不延迟", got)
+	}
+
+	// 以错误结束：缓存的 span 全部上报
+	failed := model.TraceID{Low: 1}
+	s.Defer(failed)
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: failed, ID: 1}})
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: failed, ID: 2}})
+	if got := len(rec.Flush()); got != 0 {
+		t.Fatalf("Resolve 之前上报了 %d 个 span, want 0", got)
+	}
+	s.Resolve(failed, true)
+	if got := len(rec.Flush()); got != 2 {
+		t.Errorf("以错误结束时上报 %d 个 span, want 2", got)
+	}
+	// Resolve 之后结束的异步子 span 同样上报
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: failed, ID: 5}})
+	if got := len(rec.Flush()); got != 1 {
+		t.Errorf("以错误结束后结束的 span 上报 %d 个, want 1", got)
+	}
+
+	// 成功结束：缓存的 span 全部丢弃
+	succeeded := model.TraceID{Low: 2}
+	s.Defer(succeeded)
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: succeeded, ID: 3}})
+	s.Resolve(succeeded, false)
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: succeeded, ID: 6}})
+	if got := len(rec.Flush()); got != 0 {
+		t.Errorf("成功结束时上报 %d 个 span, want 0", got)
+	}
+
+	// 未延迟决定的链路直接上报
+	r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: model.TraceID{Low: 3}, ID: 4}})
+	if got := len(rec.Flush()); got != 1 {
+		t.Errorf("未延迟决定的链路上报 %d 个 span, want 1", got)
+	}
+
+	// 子 span 与 Resolve 并发结束：以错误结束时全部上报，成功结束时全部丢弃
+	const concurrent = 200
+	for i, keep := range []bool{true, false} {
+		traceID := model.TraceID{Low: uint64(10 + i)}
+		s.Defer(traceID)
+		var wg sync.WaitGroup
+		for id := 1; id <= concurrent; id++ {
+			wg.Add(1)
+			go func(id model.ID) {
+				defer wg.Done()
+				r.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: traceID, ID: id}})
+			}(model.ID(id))
+		}
+		s.Resolve(traceID, keep)
+		wg.Wait()
+
+		want := 0
+		if keep {
+			want = concurrent
+		}
+		if got := len(rec.Flush()); got != want {
+			t.Errorf("并发结束 keep=%v: 上报 %d 个 span, want %d", keep, got, want)
+		}
+	}
+}
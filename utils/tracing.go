@@ -1,45 +1,74 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
+	"go-web-template/buildinfo"
 	"go-web-template/config"
 
 	"github.com/openzipkin/zipkin-go"
 	"github.com/openzipkin/zipkin-go/model"
-	"github.com/openzipkin/zipkin-go/reporter"
-	zipkinhttp "github.com/openzipkin/zipkin-go/reporter/http"
 	"go.uber.org/zap"
 )
 
-// InitZipkin 初始化Zipkin追踪
-func InitZipkin(cfg *config.ZipkinConfig, logger *zap.Logger) (*zipkin.Tracer, error) {
-	// 创建HTTP reporter
-	httpReporter := zipkinhttp.NewReporter(cfg.Endpoint)
+// Zipkin 初始化后的Zipkin追踪，持有 tracer、采样器与 reporter
+type Zipkin struct {
+	Tracer   *zipkin.Tracer
+	Sampler  *ZipkinSampler
+	Reporter *ZipkinReporter
+}
+
+// InitZipkin 初始化Zipkin追踪，采样由 sampler 决定
+func InitZipkin(cfg *config.ZipkinConfig, sampler *ZipkinSampler, logger *zap.Logger) (*Zipkin, error) {
+	// 创建HTTP reporter，延迟决定采样的链路先由 sampler 缓存
+	zipkinReporter := NewZipkinReporter(cfg.Endpoint, &cfg.Reporter, logger)
 
 	// 创建tracer
 	tracer, err := zipkin.NewTracer(
-		httpReporter,
+		sampler.Reporter(zipkinReporter),
 		zipkin.WithLocalEndpoint(&model.Endpoint{
 			ServiceName: cfg.ServiceName,
 		}),
-		zipkin.WithSampler(zipkin.NewModuloSampler(uint64(1.0/cfg.SampleRate))),
+		zipkin.WithSampler(sampler.Sample),
+		zipkin.WithTags(buildinfo.Tags()),
 	)
 
 	if err != nil {
-		httpReporter.Close()
+		zipkinReporter.Close()
 		return nil, fmt.Errorf("创建Zipkin tracer失败: %w", err)
 	}
 
 	logger.Info("Zipkin tracer创建成功",
 		zap.String("service_name", cfg.ServiceName),
 		zap.String("endpoint", cfg.Endpoint),
+		zap.String("sampler", cfg.Sampler),
 		zap.Float64("sample_rate", cfg.SampleRate),
+		zap.Bool("parent_based", cfg.ParentBased),
+		zap.Int("rules", len(cfg.Rules)),
+		zap.Int("batch_size", cfg.Reporter.BatchSize),
+		zap.Int("max_backlog", cfg.Reporter.MaxBacklog),
 	)
 
-	return tracer, nil
+	return &Zipkin{Tracer: tracer, Sampler: sampler, Reporter: zipkinReporter}, nil
+}
+
+// Close 上报缓冲中的 span 并关闭 reporter，ctx 到期时不再等待
+func (z *Zipkin) Close(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- z.Reporter.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("等待Zipkin上报剩余span超时: %w", ctx.Err())
+	}
 }
 
 // ZipkinHealthChecker Zipkin健康检查器
@@ -58,41 +87,67 @@ func NewZipkinHealthChecker(endpoint string, logger *zap.Logger) *ZipkinHealthCh
 
 // CheckConnection 检查Zipkin连接状态
 func (z *ZipkinHealthChecker) CheckConnection() bool {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// 尝试访问Zipkin健康检查端点
-	healthEndpoint := fmt.Sprintf("%s/../health", z.endpoint)
-	resp, err := client.Get(healthEndpoint)
-	if err != nil {
+	if err := z.Ping(ctx); err != nil {
 		z.logger.Debug("Zipkin健康检查失败", zap.Error(err))
 		return false
 	}
+	return true
+}
+
+// Ping 访问Zipkin的 /health 端点，非 200 响应视为不可达
+func (z *ZipkinHealthChecker) Ping(ctx context.Context) error {
+	endpoint, err := url.Parse(z.endpoint)
+	if err != nil {
+		return fmt.Errorf("Zipkin地址不合法: %w", err)
+	}
+	healthEndpoint := endpoint.ResolveReference(&url.URL{Path: "/health"})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthEndpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Zipkin健康检查返回状态码 %d", resp.StatusCode)
+	}
+	return nil
 }
 
-// MonitorZipkinReporter 监控Zipkin reporter状态
-func MonitorZipkinReporter(tracer *zipkin.Tracer, logger *zap.Logger) {
-	// 这是一个简单的监控示例
-	// 在实际项目中，你可能需要更复杂的监控逻辑
+// MonitorZipkinReporter 定期记录 span 上报统计，有 span 被丢弃时记录警告，reporter 关闭后停止
+func MonitorZipkinReporter(r *ZipkinReporter, logger *zap.Logger) {
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			logger.Debug("Zipkin tracer运行状态检查")
-			// 这里可以添加更多的监控逻辑
+		var last ZipkinReporterStats
+		for {
+			select {
+			case <-r.Done():
+				return
+			case <-ticker.C:
+			}
+
+			stats := r.Stats()
+			fields := []zap.Field{
+				zap.Uint64("received", stats.Received-last.Received),
+				zap.Uint64("reported", stats.Reported-last.Reported),
+				zap.Uint64("dropped", stats.Dropped-last.Dropped),
+				zap.Uint64("dropped_total", stats.Dropped),
+			}
+			if stats.Dropped > last.Dropped {
+				logger.Warn("Zipkin span上报有丢弃", fields...)
+			} else {
+				logger.Debug("Zipkin span上报状态", fields...)
+			}
+			last = stats
 		}
 	}()
 }
-
-// CloseReporter 关闭reporter（优雅关闭时使用）
-func CloseReporter(rep reporter.Reporter) error {
-	if rep != nil {
-		return rep.Close()
-	}
-	return nil
-}